@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"time"
 	"v2/be/internal/app"
+	"v2/be/internal/blob"
 	"v2/be/internal/db"
 	"v2/be/internal/models"
 
@@ -31,12 +34,52 @@ func main() {
 		panic(err)
 	}
 
-	m := models.New(pool)
+	attachments := "attachments"
+	if s, ok := os.LookupEnv("ATTACHMENTS_DIR"); ok {
+		attachments = s
+	}
+
+	blobs, err := blob.NewFS(attachments)
+	if err != nil {
+		panic(err)
+	}
+
+	m := models.New(pool, blobs)
 
 	sessions := scs.New()
 	sessions.Store = pgxstore.New(pool)
 
-	r := app.Routes(sessions, logger, m.Users, m.Tasks)
+	retention := models.DefaultTrashRetention
+	if s, ok := os.LookupEnv("TRASH_RETENTION"); ok {
+		retention, err = time.ParseDuration(s)
+		if err != nil {
+			panic(err)
+		}
+
+		// a retention of zero or less would purge tasks as soon as they are trashed
+		if retention <= 0 {
+			panic("trash retention must be positive")
+		}
+	}
+
+	if s, ok := os.LookupEnv("IDEMPOTENCY_TTL"); ok {
+		m.Idempotency.TTL, err = time.ParseDuration(s)
+		if err != nil {
+			panic(err)
+		}
+
+		if m.Idempotency.TTL <= 0 {
+			panic("idempotency ttl must be positive")
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go app.PurgeTrash(ctx, logger, m.Tasks, retention, time.Hour)
+	go app.PurgeIdempotencyKeys(ctx, logger, m.Idempotency, time.Hour)
+
+	r := app.Routes(sessions, logger, m.Users, m.Tasks, m.Tags, m.Projects, m.Workflows, m.Comments, m.Attachments, m.Shares, m.Access, m.Workspaces, m.Templates, m.Idempotency)
 
 	srv := &http.Server{
 		Addr:     ":4444",
@@ -15,6 +15,7 @@ require (
 	github.com/ory/dockertest/v3 v3.10.0
 	github.com/pseidemann/finish v1.2.0
 	github.com/stretchr/testify v1.9.0
+	github.com/teambition/rrule-go v1.8.2
 	github.com/wagslane/go-password-validator v0.3.0
 	go.uber.org/zap v1.27.0
 )
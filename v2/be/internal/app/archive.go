@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+	"v2/be/internal/validator"
+
+	"go.uber.org/zap"
+)
+
+type TaskArchiver interface {
+	Archive(ctx context.Context, id, userID string) error
+}
+
+// HandleArchiveTask moves a completed task and its completed subtasks to the archive
+func HandleArchiveTask(logger *zap.Logger, ta TaskArchiver) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		userID := GetOwnerID(r)
+
+		// archiving twice leaves the task as it was
+		err := ta.Archive(r.Context(), id, userID)
+		if err != nil && !errors.Is(err, models.ErrTaskArchived) {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			case errors.Is(err, models.ErrTaskNotCompleted):
+				ConflictingStateError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": "archived"})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type CompletedArchiver interface {
+	ArchiveCompleted(ctx context.Context, userID string) (int64, error)
+}
+
+// HandleArchiveCompleted moves every completed task of the user to the archive
+// and returns how many tasks were archived
+func HandleArchiveCompleted(logger *zap.Logger, ca CompletedArchiver) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		n, err := ca.ArchiveCompleted(r.Context(), id)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": n})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+// HandleListArchivedTasks returns a page of the user's archived tasks, taking
+// the same filters as HandleListTasks
+func HandleListArchivedTasks(logger *zap.Logger, tl TaskLister) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		v := validator.New()
+		f := readTaskFilter(r, v)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		f.Archived = true
+
+		tasks, next, err := tl.All(r.Context(), id, f)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		if tasks == nil {
+			tasks = []*models.Task{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": tasks, "next_cursor": next})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
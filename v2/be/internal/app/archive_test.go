@@ -0,0 +1,115 @@
+package app_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandleArchiveTask(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		code int
+	}{
+		{name: "valid", tid: db.NewID(), code: http.StatusOK},
+		{name: "missing task", tid: "1", code: http.StatusNotFound},
+		{name: "open task", tid: "409", code: http.StatusConflict},
+		{name: "already archived", tid: "304", code: http.StatusOK},
+		{name: "archive error", tid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			h := app.HandleArchiveTask(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleArchiveCompleted(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		code int
+	}{
+		{name: "valid", uid: db.NewID(), code: http.StatusOK},
+		{name: "archive error", uid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+			h := app.HandleArchiveCompleted(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleListArchivedTasks(t *testing.T) {
+	tests := []struct {
+		name  string
+		uid   string
+		query string
+		code  int
+		body  string
+	}{
+		{name: "valid", uid: db.NewID(), query: "?limit=10&sort=-created", code: http.StatusOK, body: "next_cursor"},
+		{name: "empty", uid: "1", code: http.StatusOK, body: `"payload":[]`},
+		{name: "bad limit", uid: db.NewID(), query: "?limit=1000", code: http.StatusUnprocessableEntity},
+		{name: "list error", uid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+
+			h := app.HandleListArchivedTasks(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.body != "" {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+
+	"go.uber.org/zap"
+)
+
+type TaskAssigner interface {
+	TaskGetter
+	Assign(ctx context.Context, id, userID string, assigneeID *string) error
+}
+
+// HandleAssignTask makes another user responsible for a task, a null assignee_id unassigns it
+func HandleAssignTask(logger *zap.Logger, ta TaskAssigner) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		userID := GetOwnerID(r)
+
+		var input struct {
+			AssigneeID *string `json:"assignee_id"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		t, err := ta.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		if sameAssignee(t.AssigneeID, input.AssigneeID) {
+			err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t.ID})
+			if err != nil {
+				writeError(w)
+			}
+			return
+		}
+
+		err = ta.Assign(r.Context(), t.ID, userID, input.AssigneeID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			case errors.Is(err, models.ErrAssigneeNotFound):
+				InvalidDataError(w, map[string]string{"assignee_id": err.Error()})
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t.ID})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+func sameAssignee(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
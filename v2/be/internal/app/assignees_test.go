@@ -0,0 +1,52 @@
+package app_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandleAssignTask(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		body string
+		code int
+	}{
+		{name: "valid", tid: db.NewID(), body: `{"assignee_id": "` + db.NewID() + `"}`, code: http.StatusOK},
+		{name: "bad body", tid: db.NewID(), body: `{"assignee": "ada"}`, code: http.StatusBadRequest},
+		{name: "missing task", tid: "1", body: `{"assignee_id": "7"}`, code: http.StatusNotFound},
+		{name: "get error", tid: "25", body: `{"assignee_id": "7"}`, code: http.StatusInternalServerError},
+		{name: "already unassigned", tid: db.NewID(), body: `{"assignee_id": null}`, code: http.StatusOK},
+		{name: "unknown user", tid: db.NewID(), body: `{"assignee_id": "1"}`, code: http.StatusUnprocessableEntity},
+		{name: "assign error", tid: db.NewID(), body: `{"assignee_id": "25"}`, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPatch, "/", bytes.NewBufferString(tt.body))
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			h := app.HandleAssignTask(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
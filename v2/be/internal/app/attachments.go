@@ -0,0 +1,181 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+	"v2/be/internal/validator"
+
+	"go.uber.org/zap"
+)
+
+type AttachmentCreater interface {
+	Create(ctx context.Context, a *models.Attachment, r io.Reader) error
+}
+
+// HandleUploadAttachment attaches the file sent as the "file" field of a multipart form to a task
+func HandleUploadAttachment(logger *zap.Logger, ac AttachmentCreater) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		userID := GetUserID(r)
+
+		f, err := parser.ReadFile(w, r, "file", models.MaxAttachmentSize)
+		if err != nil {
+			switch {
+			case errors.Is(err, parser.ErrFileTooLarge):
+				OversizedDataError(w, logger, err)
+			default:
+				ReadError(w, logger, err)
+			}
+			return
+		}
+
+		name := parser.Sanitize(f.Name)
+
+		v := validator.New()
+		v.RequiredString(name, "name", validator.Required)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		a := &models.Attachment{
+			ID:          db.NewID(),
+			TaskID:      id,
+			UserID:      userID,
+			Name:        name,
+			ContentType: f.ContentType,
+		}
+
+		err = ac.Create(r.Context(), a, f.Body)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			case errors.Is(err, parser.ErrFileTooLarge),
+				errors.Is(err, models.ErrQuotaExceeded):
+				OversizedDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusCreated, parser.Envelope{"payload": a})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type AttachmentLister interface {
+	All(ctx context.Context, taskID, userID string) ([]*models.Attachment, error)
+}
+
+func HandleListAttachments(logger *zap.Logger, tg TaskGetter, al AttachmentLister) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		userID := GetOwnerID(r)
+
+		t, err := tg.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		// the list is authorized for the user acting, not for the task's owner
+		attachments, err := al.All(r.Context(), t.ID, GetUserID(r))
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		if attachments == nil {
+			attachments = []*models.Attachment{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": attachments})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type AttachmentOpener interface {
+	Open(ctx context.Context, id, userID string) (*models.Attachment, io.ReadCloser, error)
+}
+
+// HandleDownloadAttachment sends the content of an attachment as a file download
+func HandleDownloadAttachment(logger *zap.Logger, ao AttachmentOpener) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetAttachmentID(r)
+		userID := GetUserID(r)
+
+		a, content, err := ao.Open(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		defer content.Close()
+
+		w.Header().Set("Content-Type", a.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Name}))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+
+		// the status is already sent, so a failed copy can only be logged
+		_, err = io.Copy(w, content)
+		logError(logger, err)
+	})
+}
+
+type AttachmentDeleter interface {
+	Delete(ctx context.Context, id, userID string) error
+}
+
+func HandleDeleteAttachment(logger *zap.Logger, ad AttachmentDeleter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetAttachmentID(r)
+		userID := GetUserID(r)
+
+		err := ad.Delete(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": "deleted"})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setAttachmentID(t *testing.T, id string) context.Context {
+	t.Helper()
+
+	rtx := chi.NewRouteContext()
+	rtx.URLParams.Add("attachment_id", id)
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rtx)
+
+	return ctx
+}
+
+// multipartBody returns a multipart form holding content as the file name under field
+func multipartBody(t *testing.T, field, name string, content []byte) (*bytes.Buffer, string) {
+	t.Helper()
+
+	var b bytes.Buffer
+
+	mw := multipart.NewWriter(&b)
+
+	fw, err := mw.CreateFormFile(field, name)
+	require.NoError(t, err)
+
+	_, err = fw.Write(content)
+	require.NoError(t, err)
+
+	require.NoError(t, mw.Close())
+
+	return &b, mw.FormDataContentType()
+}
+
+func TestHandleUploadAttachment(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+
+	tests := []struct {
+		name    string
+		tid     string
+		field   string
+		content []byte
+		want    string
+		code    int
+	}{
+		{name: "valid", tid: db.NewID(), field: "file", content: png, want: "image/png", code: http.StatusCreated},
+		{name: "wrong field", tid: db.NewID(), field: "upload", content: png, code: http.StatusBadRequest},
+		{name: "empty file", tid: db.NewID(), field: "file", content: nil, code: http.StatusBadRequest},
+		{
+			name:    "too large",
+			tid:     db.NewID(),
+			field:   "file",
+			content: bytes.Repeat([]byte("a"), models.MaxAttachmentSize+1),
+			code:    http.StatusRequestEntityTooLarge,
+		},
+		{name: "missing task", tid: "1", field: "file", content: png, code: http.StatusNotFound},
+		{name: "quota exceeded", tid: "413", field: "file", content: png, code: http.StatusRequestEntityTooLarge},
+		{name: "create error", tid: "25", field: "file", content: png, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			body, contentType := multipartBody(t, tt.field, "shot.png", tt.content)
+
+			r := httptest.NewRequest(http.MethodPost, "/", body)
+			r.Header.Set("Content-Type", contentType)
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			h := app.HandleUploadAttachment(zap.NewNop(), testdata.NewAM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusCreated {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), tt.want)
+			}
+		})
+	}
+
+	t.Run("not multipart", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"file": "shot.png"}`))
+		r = r.WithContext(setTaskID(t, db.NewID()))
+
+		h := app.HandleUploadAttachment(zap.NewNop(), testdata.NewAM())
+
+		session := scs.New()
+		m := lsm(t, session, db.NewID())
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestHandleListAttachments(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		uid  string
+		want string
+		code int
+	}{
+		{name: "valid", tid: db.NewID(), uid: db.NewID(), want: "notes.txt", code: http.StatusOK},
+		{name: "no attachments", tid: "204", uid: db.NewID(), want: `"payload":[]`, code: http.StatusOK},
+		{name: "missing task", tid: "1", uid: db.NewID(), code: http.StatusNotFound},
+		{name: "get error", tid: "25", uid: db.NewID(), code: http.StatusInternalServerError},
+		{name: "list error", tid: db.NewID(), uid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			h := app.HandleListAttachments(zap.NewNop(), testdata.NewTM(), testdata.NewAM())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusOK {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleDownloadAttachment(t *testing.T) {
+	tests := []struct {
+		name string
+		aid  string
+		code int
+	}{
+		{name: "valid", aid: db.NewID(), code: http.StatusOK},
+		{name: "missing attachment", aid: "1", code: http.StatusNotFound},
+		{name: "open error", aid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(setAttachmentID(t, tt.aid))
+
+			h := app.HandleDownloadAttachment(zap.NewNop(), testdata.NewAM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusOK {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Equal(t, "hello", readTestBody(t, rs.Body))
+				require.Equal(t, "text/plain; charset=utf-8", rs.Header.Get("Content-Type"))
+				require.Equal(t, `attachment; filename=notes.txt`, rs.Header.Get("Content-Disposition"))
+				require.Equal(t, "nosniff", rs.Header.Get("X-Content-Type-Options"))
+			}
+		})
+	}
+}
+
+func TestHandleDeleteAttachment(t *testing.T) {
+	tests := []struct {
+		name string
+		aid  string
+		code int
+	}{
+		{name: "valid", aid: db.NewID(), code: http.StatusOK},
+		{name: "missing attachment", aid: "1", code: http.StatusNotFound},
+		{name: "delete error", aid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			r = r.WithContext(setAttachmentID(t, tt.aid))
+
+			h := app.HandleDeleteAttachment(zap.NewNop(), testdata.NewAM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+	"v2/be/internal/validator"
+
+	"go.uber.org/zap"
+)
+
+var ErrBulkConflict = errors.New("task was changed by another operation")
+
+// bulkInput is one operation of a bulk request. The fields besides Op and
+// TaskID are read by the operations that take them, as in their own routes.
+type bulkInput struct {
+	Op          string     `json:"op"`
+	TaskID      string     `json:"task_id"`
+	Cascade     bool       `json:"cascade"`
+	Tags        []string   `json:"tags"`
+	Before      string     `json:"before"`
+	After       string     `json:"after"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	DueAt       *time.Time `json:"due_at"`
+	RemindAt    *time.Time `json:"remind_at"`
+	Recurrence  *string    `json:"recurrence"`
+	Priority    string     `json:"priority"`
+	ProjectID   string     `json:"project_id"`
+}
+
+// bulkResult is the outcome of one operation of a bulk request, with the
+// status code its own route would have answered
+type bulkResult struct {
+	TaskID string `json:"task_id"`
+	Op     string `json:"op"`
+	Status int    `json:"status"`
+	Error  any    `json:"error,omitempty"`
+}
+
+// readBulkOp validates in and turns it into the operation run by the model
+func readBulkOp(in *bulkInput, v *validator.Validator) models.BulkOp {
+	in.TaskID = strings.TrimSpace(in.TaskID)
+
+	v.RequiredString(in.TaskID, "task_id", validator.Required)
+	v.Check(validator.PermittedValue(in.Op, models.BulkOps...), "op", "invalid op value")
+
+	op := models.BulkOp{Op: in.Op, TaskID: in.TaskID}
+
+	switch in.Op {
+	case models.BulkComplete:
+		op.Cascade = in.Cascade
+	case models.BulkTag:
+		op.Tags = cleanTags(in.Tags, v)
+		v.Check(len(op.Tags) > 0, "tags", "must contain at least one name")
+	case models.BulkMove:
+		in.Before = strings.TrimSpace(in.Before)
+		in.After = strings.TrimSpace(in.After)
+
+		v.Check((in.Before == "") != (in.After == ""), "before", "exactly one of before or after is required")
+		v.Check(in.Before != in.TaskID && in.After != in.TaskID, "before", "a task cannot be moved next to itself")
+
+		op.TargetID, op.After = in.Before, false
+		if in.After != "" {
+			op.TargetID, op.After = in.After, true
+		}
+	case models.BulkUpdate:
+		in.Title = parser.Sanitize(in.Title)
+		in.Description = parser.Sanitize(in.Description)
+
+		v.RequiredString(in.Title, "title", validator.Required)
+		v.RequiredString(in.Description, "description", validator.Required)
+		validateTaskDates(v, in.DueAt, in.RemindAt)
+		v.Check(in.Priority == "" || validator.PermittedValue(in.Priority, models.TaskPriorities...), "priority", "invalid priority value")
+
+		op.Task = &models.Task{
+			Title:       in.Title,
+			Description: in.Description,
+			DueAt:       in.DueAt,
+			RemindAt:    in.RemindAt,
+			Recurrence:  cleanRecurrence(in.Recurrence, v),
+			Priority:    in.Priority,
+			Tags:        cleanTags(in.Tags, v),
+			ProjectID:   strings.TrimSpace(in.ProjectID),
+		}
+	}
+
+	return op
+}
+
+// bulkStatus returns the status code and error the route of an operation
+// would have answered with err
+func bulkStatus(logger *zap.Logger, err error) (int, any) {
+	switch {
+	case err == nil:
+		return http.StatusOK, nil
+	case errors.Is(err, models.ErrRecordNotFound), errors.Is(err, models.ErrProjectNotFound),
+		errors.Is(err, models.ErrMoveTargetNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, models.ErrTaskCompleted), errors.Is(err, models.ErrDuplicateTask), errors.Is(err, models.ErrOpenSubtasks),
+		errors.Is(err, models.ErrOpenBlockers), errors.Is(err, models.ErrInvalidTransition):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, models.ErrOpFailed):
+		// the row no longer matched, as when an earlier operation completed or deleted it
+		return http.StatusConflict, ErrBulkConflict.Error()
+	case errors.Is(err, models.ErrEditConflict):
+		return http.StatusPreconditionFailed, err.Error()
+	case errors.Is(err, models.ErrInvalidRecurrence), errors.Is(err, models.ErrStatusNotFound):
+		return http.StatusUnprocessableEntity, err.Error()
+	default:
+		logError(logger, err)
+		return http.StatusInternalServerError, "request could no longer be processed"
+	}
+}
+
+type TaskBulker interface {
+	Bulk(ctx context.Context, userID string, ops []models.BulkOp) ([]*models.BulkResult, error)
+}
+
+// HandleBulkTasks runs a list of operations on the user's tasks in a single
+// transaction and returns the result of each. Invalid operations are reported
+// without being run and do not stop the others.
+func HandleBulkTasks(logger *zap.Logger, tb TaskBulker) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		var input struct {
+			Operations []bulkInput `json:"operations"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		v := validator.New()
+		v.Check(len(input.Operations) > 0, "operations", "must contain at least one operation")
+		v.Check(len(input.Operations) <= models.MaxBulkOps, "operations", "must not contain more than 100 operations")
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		results := make([]*bulkResult, len(input.Operations))
+
+		var ops []models.BulkOp
+		var pending []int
+
+		for i := range input.Operations {
+			in := &input.Operations[i]
+
+			ov := validator.New()
+			op := readBulkOp(in, ov)
+
+			results[i] = &bulkResult{TaskID: in.TaskID, Op: in.Op}
+			if !ov.Valid() {
+				results[i].Status, results[i].Error = http.StatusUnprocessableEntity, ov.Errors()
+				continue
+			}
+
+			ops = append(ops, op)
+			pending = append(pending, i)
+		}
+
+		if len(ops) > 0 {
+			applied, berr := tb.Bulk(r.Context(), id, ops)
+			if berr != nil {
+				ServerError(w, logger, berr)
+				return
+			}
+
+			for j, res := range applied {
+				results[pending[j]].Status, results[pending[j]].Error = bulkStatus(logger, res.Err)
+			}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": results})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package app_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandleBulkTasks(t *testing.T) {
+	t.Run("results", func(t *testing.T) {
+		t.Parallel()
+
+		id := db.NewID()
+		body := `{"operations": [
+			{"op": "complete", "task_id": "` + id + `", "cascade": true},
+			{"op": "delete", "task_id": "1"},
+			{"op": "tag", "task_id": "` + id + `", "tags": ["urgent", "urgent"]},
+			{"op": "move", "task_id": "` + id + `", "after": "` + db.NewID() + `"},
+			{"op": "update", "task_id": "304", "title": "Ship", "description": "Ship it"},
+			{"op": "complete", "task_id": "409"},
+			{"op": "delete", "task_id": "25"},
+			{"op": "archive", "task_id": "` + id + `"},
+			{"op": "update", "task_id": "` + id + `", "title": ""},
+			{"op": "update", "task_id": "202", "title": "Ship", "description": "Ship it"},
+			{"op": "delete", "task_id": "412"},
+			{"op": "complete", "task_id": "422"}
+		]}`
+
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+
+		h := app.HandleBulkTasks(zap.NewNop(), testdata.NewTM())
+
+		session := scs.New()
+		m := lsm(t, session, db.NewID())
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		rs := rr.Result()
+		defer rs.Body.Close()
+
+		var out struct {
+			Payload []struct {
+				TaskID string `json:"task_id"`
+				Op     string `json:"op"`
+				Status int    `json:"status"`
+				Error  any    `json:"error"`
+			} `json:"payload"`
+		}
+		require.NoError(t, json.NewDecoder(rs.Body).Decode(&out))
+
+		var statuses []int
+		for _, p := range out.Payload {
+			statuses = append(statuses, p.Status)
+		}
+
+		require.Equal(t, []int{
+			http.StatusOK,
+			http.StatusNotFound,
+			http.StatusOK,
+			http.StatusOK,
+			http.StatusConflict,
+			http.StatusConflict,
+			http.StatusInternalServerError,
+			http.StatusUnprocessableEntity,
+			http.StatusUnprocessableEntity,
+			http.StatusConflict,
+			http.StatusPreconditionFailed,
+			http.StatusUnprocessableEntity,
+		}, statuses)
+		require.Equal(t, "1", out.Payload[1].TaskID)
+		require.Equal(t, models.ErrTaskCompleted.Error(), out.Payload[4].Error)
+		require.Equal(t, "archive", out.Payload[7].Op)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		many := make([]string, 101)
+		for i := range many {
+			many[i] = `{"op": "delete", "task_id": "7"}`
+		}
+
+		tests := []struct {
+			name string
+			uid  string
+			body string
+			code int
+		}{
+			{name: "bad body", uid: db.NewID(), body: `{"ops": []}`, code: http.StatusBadRequest},
+			{name: "no operations", uid: db.NewID(), body: `{"operations": []}`, code: http.StatusUnprocessableEntity},
+			{name: "too many operations", uid: db.NewID(), body: `{"operations": [` + strings.Join(many, ",") + `]}`, code: http.StatusUnprocessableEntity},
+			{name: "bulk error", uid: "25", body: `{"operations": [{"op": "delete", "task_id": "7"}]}`, code: http.StatusInternalServerError},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				rr := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.body))
+
+				h := app.HandleBulkTasks(zap.NewNop(), testdata.NewTM())
+
+				session := scs.New()
+				m := lsm(t, session, tt.uid)
+
+				session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+				require.Equal(t, tt.code, rr.Code)
+			})
+		}
+	})
+}
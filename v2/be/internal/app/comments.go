@@ -0,0 +1,216 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+	"v2/be/internal/validator"
+
+	"go.uber.org/zap"
+)
+
+var ErrNotCommentAuthor = errors.New("only the author can change a comment")
+
+type CommentCreater interface {
+	Create(ctx context.Context, c *models.Comment) error
+}
+
+func HandleCreateComment(logger *zap.Logger, cc CommentCreater) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		userID := GetUserID(r)
+
+		var input struct {
+			Body string `json:"body"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.Body = parser.Sanitize(input.Body)
+
+		v := validator.New()
+		v.RequiredString(input.Body, "body", validator.Required)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		c := &models.Comment{
+			ID:     db.NewID(),
+			TaskID: id,
+			UserID: userID,
+			Body:   input.Body,
+		}
+
+		err = cc.Create(r.Context(), c)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusCreated, parser.Envelope{"payload": c})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type CommentLister interface {
+	All(ctx context.Context, taskID, userID string) ([]*models.Comment, error)
+}
+
+// HandleListComments returns the comments of a task, oldest first
+func HandleListComments(logger *zap.Logger, tg TaskGetter, cl CommentLister) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		userID := GetOwnerID(r)
+
+		t, err := tg.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		// the list is authorized for the user acting, not for the task's owner
+		comments, err := cl.All(r.Context(), t.ID, GetUserID(r))
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		if comments == nil {
+			comments = []*models.Comment{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": comments})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type CommentGetter interface {
+	GetByID(ctx context.Context, id, userID string) (*models.Comment, error)
+}
+
+type CommentUpdater interface {
+	CommentGetter
+	Update(ctx context.Context, c *models.Comment) error
+}
+
+func HandleUpdateComment(logger *zap.Logger, cu CommentUpdater) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetCommentID(r)
+		userID := GetUserID(r)
+
+		var input struct {
+			Body string `json:"body"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.Body = parser.Sanitize(input.Body)
+
+		v := validator.New()
+		v.RequiredString(input.Body, "body", validator.Required)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		c, err := cu.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		if c.UserID != userID {
+			ForbiddenActionError(w, logger, ErrNotCommentAuthor)
+			return
+		}
+
+		c.Body = input.Body
+
+		err = cu.Update(r.Context(), c)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": c})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type CommentDeleter interface {
+	CommentGetter
+	Delete(ctx context.Context, id, userID string) error
+}
+
+func HandleDeleteComment(logger *zap.Logger, cd CommentDeleter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetCommentID(r)
+		userID := GetUserID(r)
+
+		c, err := cd.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		if c.UserID != userID {
+			ForbiddenActionError(w, logger, ErrNotCommentAuthor)
+			return
+		}
+
+		err = cd.Delete(r.Context(), c.ID, userID)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": "deleted"})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
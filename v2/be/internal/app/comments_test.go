@@ -0,0 +1,180 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setCommentID(t *testing.T, id string) context.Context {
+	t.Helper()
+
+	rtx := chi.NewRouteContext()
+	rtx.URLParams.Add("comment_id", id)
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rtx)
+
+	return ctx
+}
+
+func TestHandleCreateComment(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		body string
+		code int
+	}{
+		{name: "valid", tid: db.NewID(), body: `{"body": "looks good"}`, code: http.StatusCreated},
+		{name: "bad body", tid: db.NewID(), body: `{"text": "looks good"}`, code: http.StatusBadRequest},
+		{name: "empty body", tid: db.NewID(), body: `{"body": "<script></script>"}`, code: http.StatusUnprocessableEntity},
+		{name: "missing task", tid: "1", body: `{"body": "looks good"}`, code: http.StatusNotFound},
+		{name: "create error", tid: "25", body: `{"body": "looks good"}`, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.body))
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			h := app.HandleCreateComment(zap.NewNop(), testdata.NewCM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusCreated {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), "looks good")
+			}
+		})
+	}
+}
+
+func TestHandleListComments(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		uid  string
+		want string
+		code int
+	}{
+		{name: "valid", tid: db.NewID(), uid: db.NewID(), want: `"author"`, code: http.StatusOK},
+		{name: "no comments", tid: "204", uid: db.NewID(), want: `"payload":[]`, code: http.StatusOK},
+		{name: "missing task", tid: "1", uid: db.NewID(), code: http.StatusNotFound},
+		{name: "get error", tid: "25", uid: db.NewID(), code: http.StatusInternalServerError},
+		{name: "list error", tid: db.NewID(), uid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			h := app.HandleListComments(zap.NewNop(), testdata.NewTM(), testdata.NewCM())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusOK {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleUpdateComment(t *testing.T) {
+	tests := []struct {
+		name string
+		cid  string
+		body string
+		code int
+	}{
+		{name: "valid", cid: db.NewID(), body: `{"body": "edited"}`, code: http.StatusOK},
+		{name: "bad body", cid: db.NewID(), body: `{"text": "edited"}`, code: http.StatusBadRequest},
+		{name: "empty body", cid: db.NewID(), body: `{"body": " "}`, code: http.StatusUnprocessableEntity},
+		{name: "missing comment", cid: "1", body: `{"body": "edited"}`, code: http.StatusNotFound},
+		{name: "get error", cid: "25", body: `{"body": "edited"}`, code: http.StatusInternalServerError},
+		{name: "not author", cid: "403", body: `{"body": "edited"}`, code: http.StatusForbidden},
+		{name: "update error", cid: "26", body: `{"body": "edited"}`, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPatch, "/", bytes.NewBufferString(tt.body))
+			r = r.WithContext(setCommentID(t, tt.cid))
+
+			h := app.HandleUpdateComment(zap.NewNop(), testdata.NewCM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleDeleteComment(t *testing.T) {
+	tests := []struct {
+		name string
+		cid  string
+		code int
+	}{
+		{name: "valid", cid: db.NewID(), code: http.StatusOK},
+		{name: "missing comment", cid: "1", code: http.StatusNotFound},
+		{name: "get error", cid: "25", code: http.StatusInternalServerError},
+		{name: "not author", cid: "403", code: http.StatusForbidden},
+		{name: "delete error", cid: "26", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			r = r.WithContext(setCommentID(t, tt.cid))
+
+			h := app.HandleDeleteComment(zap.NewNop(), testdata.NewCM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+	"v2/be/internal/validator"
+
+	"go.uber.org/zap"
+)
+
+type BlockerAdder interface {
+	AddBlocker(ctx context.Context, id, blockerID, userID string) error
+}
+
+// HandleAddBlocker marks a task as blocked by another task of the same user
+func HandleAddBlocker(logger *zap.Logger, ba BlockerAdder) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		userID := GetOwnerID(r)
+
+		var input struct {
+			BlockerID string `json:"blocker_id"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.BlockerID = strings.TrimSpace(input.BlockerID)
+
+		v := validator.New()
+		v.RequiredString(input.BlockerID, "blocker_id", validator.Required)
+		v.Check(input.BlockerID != id, "blocker_id", "a task cannot block itself")
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		err = ba.AddBlocker(r.Context(), id, input.BlockerID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			case errors.Is(err, models.ErrDuplicateDependency):
+				DuplicateDataError(w, logger, err)
+			case errors.Is(err, models.ErrDependencyCycle):
+				ConflictingStateError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusCreated, parser.Envelope{"payload": input.BlockerID})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type BlockerRemover interface {
+	RemoveBlocker(ctx context.Context, id, blockerID, userID string) error
+}
+
+func HandleRemoveBlocker(logger *zap.Logger, br BlockerRemover) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		blockerID := GetBlockerID(r)
+		userID := GetOwnerID(r)
+
+		err := br.RemoveBlocker(r.Context(), id, blockerID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": "deleted"})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
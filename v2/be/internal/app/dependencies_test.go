@@ -0,0 +1,97 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setBlockerID(t *testing.T, id, blockerID string) context.Context {
+	t.Helper()
+
+	rtx := chi.NewRouteContext()
+	rtx.URLParams.Add("task_id", id)
+	rtx.URLParams.Add("blocker_id", blockerID)
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rtx)
+
+	return ctx
+}
+
+func TestHandleAddBlocker(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		body string
+		code int
+	}{
+		{name: "valid", tid: db.NewID(), body: `{"blocker_id": "` + db.NewID() + `"}`, code: http.StatusCreated},
+		{name: "bad body", tid: db.NewID(), body: `{"blocker": "2"}`, code: http.StatusBadRequest},
+		{name: "empty blocker", tid: db.NewID(), body: `{"blocker_id": " "}`, code: http.StatusUnprocessableEntity},
+		{name: "itself", tid: "7", body: `{"blocker_id": "7"}`, code: http.StatusUnprocessableEntity},
+		{name: "missing task", tid: db.NewID(), body: `{"blocker_id": "1"}`, code: http.StatusNotFound},
+		{name: "duplicate edge", tid: db.NewID(), body: `{"blocker_id": "409"}`, code: http.StatusConflict},
+		{name: "cycle", tid: db.NewID(), body: `{"blocker_id": "410"}`, code: http.StatusConflict},
+		{name: "add error", tid: db.NewID(), body: `{"blocker_id": "25"}`, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.body))
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			h := app.HandleAddBlocker(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleRemoveBlocker(t *testing.T) {
+	tests := []struct {
+		name string
+		bid  string
+		code int
+	}{
+		{name: "valid", bid: db.NewID(), code: http.StatusOK},
+		{name: "missing edge", bid: "1", code: http.StatusNotFound},
+		{name: "remove error", bid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			r = r.WithContext(setBlockerID(t, db.NewID(), tt.bid))
+
+			h := app.HandleRemoveBlocker(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
@@ -70,10 +70,52 @@ func UnauthorizedAccessError(w http.ResponseWriter, logger *zap.Logger, err erro
 	}
 }
 
-func UnmodifiedDataError(w http.ResponseWriter, logger *zap.Logger, err error) {
+// UnmodifiedDataError answers 304 Not Modified to a conditional GET whose
+// validator still matches. HTTP forbids a body with it.
+func UnmodifiedDataError(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotModified)
+}
+
+func ForbiddenActionError(w http.ResponseWriter, logger *zap.Logger, err error) {
+	logError(logger, err)
+
+	err = parser.Write(w, http.StatusForbidden, parser.Envelope{"error": err.Error()})
+	if err != nil {
+		writeError(w)
+	}
+}
+
+func ConflictingStateError(w http.ResponseWriter, logger *zap.Logger, err error) {
+	logError(logger, err)
+
+	err = parser.Write(w, http.StatusConflict, parser.Envelope{"error": err.Error()})
+	if err != nil {
+		writeError(w)
+	}
+}
+
+func OversizedDataError(w http.ResponseWriter, logger *zap.Logger, err error) {
+	logError(logger, err)
+
+	err = parser.Write(w, http.StatusRequestEntityTooLarge, parser.Envelope{"error": err.Error()})
+	if err != nil {
+		writeError(w)
+	}
+}
+
+func PreconditionFailedError(w http.ResponseWriter, logger *zap.Logger, err error) {
+	logError(logger, err)
+
+	err = parser.Write(w, http.StatusPreconditionFailed, parser.Envelope{"error": err.Error()})
+	if err != nil {
+		writeError(w)
+	}
+}
+
+func PreconditionRequiredError(w http.ResponseWriter, logger *zap.Logger, err error) {
 	logError(logger, err)
 
-	err = parser.Write(w, http.StatusNotModified, parser.Envelope{"error": err.Error()})
+	err = parser.Write(w, http.StatusPreconditionRequired, parser.Envelope{"error": err.Error()})
 	if err != nil {
 		writeError(w)
 	}
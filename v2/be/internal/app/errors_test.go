@@ -119,13 +119,88 @@ func TestUnmodifiedDataError(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 
-	app.UnmodifiedDataError(rr, zap.NewNop(), errors.New("not modified"))
+	app.UnmodifiedDataError(rr)
 	require.Equal(t, http.StatusNotModified, rr.Code)
+	require.Zero(t, rr.Body.Len())
+}
+
+func TestForbiddenActionError(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+
+	app.ForbiddenActionError(rr, zap.NewNop(), errors.New("forbidden action"))
+	require.Equal(t, http.StatusForbidden, rr.Code)
+
+	rs := rr.Result()
+	defer rs.Body.Close()
+
+	body := readTestBody(t, rs.Body)
+
+	require.Contains(t, body, "forbidden action")
+}
+
+func TestConflictingStateError(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+
+	app.ConflictingStateError(rr, zap.NewNop(), errors.New("conflicting state"))
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	rs := rr.Result()
+	defer rs.Body.Close()
+
+	body := readTestBody(t, rs.Body)
+
+	require.Contains(t, body, "conflicting state")
+}
+
+func TestOversizedDataError(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+	logger := zap.NewNop()
+
+	app.OversizedDataError(rr, logger, errors.New("too large"))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+	rs := rr.Result()
+	defer rs.Body.Close()
+
+	body := readTestBody(t, rs.Body)
+
+	require.Contains(t, body, "too large")
+}
+
+func TestPreconditionFailedError(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+
+	app.PreconditionFailedError(rr, zap.NewNop(), errors.New("precondition failed"))
+	require.Equal(t, http.StatusPreconditionFailed, rr.Code)
+
+	rs := rr.Result()
+	defer rs.Body.Close()
+
+	body := readTestBody(t, rs.Body)
+
+	require.Contains(t, body, "precondition failed")
+}
+
+func TestPreconditionRequiredError(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+
+	app.PreconditionRequiredError(rr, zap.NewNop(), errors.New("precondition required"))
+	require.Equal(t, http.StatusPreconditionRequired, rr.Code)
 
 	rs := rr.Result()
 	defer rs.Body.Close()
 
 	body := readTestBody(t, rs.Body)
 
-	require.Contains(t, body, "not modified")
+	require.Contains(t, body, "precondition required")
 }
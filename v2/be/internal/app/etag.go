@@ -0,0 +1,108 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrPreconditionRequired = errors.New("request must send If-Match with the ETag of the task")
+	ErrPreconditionFailed   = errors.New("task was changed since it was read")
+)
+
+// taskETag returns the entity tag of a version of a task. When body is given
+// the tag also carries a digest of it, so that it changes with anything the
+// representation shows besides the task's own fields, such as subtasks,
+// dependencies or the overdue flag.
+func taskETag(version int64, body []byte) string {
+	tag := strconv.FormatInt(version, 10)
+	if body != nil {
+		sum := sha256.Sum256(body)
+		tag += "-" + hex.EncodeToString(sum[:8])
+	}
+
+	return `"` + tag + `"`
+}
+
+// tagVersion returns the task version an entity tag was made from
+func tagVersion(etag string) (int64, bool) {
+	tag, ok := strings.CutPrefix(etag, `"`)
+	if !ok {
+		return 0, false
+	}
+
+	tag, ok = strings.CutSuffix(tag, `"`)
+	if !ok {
+		return 0, false
+	}
+
+	tag, _, _ = strings.Cut(tag, "-")
+
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return version, true
+}
+
+// matchETag reports whether the If-None-Match header value lists etag or is
+// "*", comparing tags weakly
+func matchETag(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if tag == "*" {
+			return true
+		}
+
+		if strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchVersion reports whether the If-Match header value lists a strong tag
+// of the given version of the task or is "*". Only the version is compared,
+// as writes are guarded against changes to the task itself.
+func matchVersion(header string, version int64) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if tag == "*" {
+			return true
+		}
+
+		v, ok := tagVersion(tag)
+		if ok && v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkIfMatch answers 428 when r carries no If-Match header and 412 when it
+// does not match the given version of the task, reporting whether r may go on
+func checkIfMatch(w http.ResponseWriter, r *http.Request, logger *zap.Logger, version int64) bool {
+	header := r.Header.Get("If-Match")
+
+	switch {
+	case header == "":
+		PreconditionRequiredError(w, logger, ErrPreconditionRequired)
+		return false
+	case !matchVersion(header, version):
+		PreconditionFailedError(w, logger, ErrPreconditionFailed)
+		return false
+	default:
+		return true
+	}
+}
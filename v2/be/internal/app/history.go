@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+
+	"go.uber.org/zap"
+)
+
+type TaskHistorian interface {
+	TaskGetter
+	History(ctx context.Context, id, userID string) ([]*models.TaskEvent, error)
+}
+
+// HandleTaskHistory returns what happened to a task, oldest event first
+func HandleTaskHistory(logger *zap.Logger, th TaskHistorian) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		userID := GetOwnerID(r)
+
+		t, err := th.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		events, err := th.History(r.Context(), t.ID, userID)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		if events == nil {
+			events = []*models.TaskEvent{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": events})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
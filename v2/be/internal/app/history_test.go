@@ -0,0 +1,57 @@
+package app_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandleTaskHistory(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		uid  string
+		want string
+		code int
+	}{
+		{name: "valid", tid: db.NewID(), uid: db.NewID(), want: `"action":"updated"`, code: http.StatusOK},
+		{name: "no events", tid: "204", uid: db.NewID(), want: `"payload":[]`, code: http.StatusOK},
+		{name: "missing task", tid: "1", uid: db.NewID(), code: http.StatusNotFound},
+		{name: "get error", tid: "25", uid: db.NewID(), code: http.StatusInternalServerError},
+		{name: "history error", tid: db.NewID(), uid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			h := app.HandleTaskHistory(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusOK {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), tt.want)
+			}
+		})
+	}
+}
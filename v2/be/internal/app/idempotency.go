@@ -0,0 +1,194 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+
+	"go.uber.org/zap"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKey    = 255
+	maxIdempotentBody    = 1_048_576
+)
+
+var ErrIdempotencyKey = fmt.Errorf("idempotency key must not be longer than %d characters", maxIdempotencyKey)
+
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, userID, key string, fingerprint []byte) (*models.StoredResponse, error)
+	Save(ctx context.Context, userID, key string, res *models.StoredResponse) error
+	Release(ctx context.Context, userID, key string) error
+}
+
+// responseRecorder passes a response through while keeping a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(code int) {
+	if rec.status == 0 {
+		rec.status = code
+	}
+
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+
+	rec.body.Write(b)
+
+	return rec.ResponseWriter.Write(b)
+}
+
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// fingerprint identifies a request by its method, target and body
+func fingerprint(r *http.Request, body []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+
+	return h.Sum(nil)
+}
+
+// Idempotent returns a middleware that honours the Idempotency-Key header on
+// POST requests. The first response to a key is stored per user and replayed
+// to retries, and reusing a key for a different request is rejected, except
+// for anonymous requests whose keys are scoped to the request instead. Only the
+// status, Content-Type and body are replayed, so a replayed signup does not
+// log the client in. Server errors are not stored and the request may be retried.
+// Multipart uploads are passed through, as their bodies are too large to keep.
+func Idempotent(logger *zap.Logger, is IdempotencyStore) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyKeyHeader)
+			if r.Method != http.MethodPost || key == "" || parser.HasContentType(r, "multipart/form-data") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKey {
+				ReadError(w, logger, ErrIdempotencyKey)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBody))
+			if err != nil {
+				var maxBytesError *http.MaxBytesError
+				if errors.As(err, &maxBytesError) {
+					err = fmt.Errorf("body must not be larger than %d bytes", maxBytesError.Limit)
+				}
+
+				ReadError(w, logger, err)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			fp := fingerprint(r, body)
+
+			// signup is not behind authentication, so its keys are kept without
+			// a user and unrelated clients may pick the same one. Their keys are
+			// scoped to the request they came with: a retry is still replayed,
+			// but reusing a key for another request is not detected, and only
+			// clients sending the very same request with the same key share it.
+			id, _ := r.Context().Value(userID).(string)
+			if id == "" {
+				key += ":" + hex.EncodeToString(fp)
+			}
+
+			stored, err := is.Reserve(r.Context(), id, key, fp)
+			if err != nil {
+				switch {
+				case errors.Is(err, models.ErrIdempotencyKeyReused):
+					InvalidDataError(w, map[string]string{idempotencyKeyHeader: err.Error()})
+				case errors.Is(err, models.ErrIdempotencyKeyInFlight):
+					ConflictingStateError(w, logger, err)
+				default:
+					ServerError(w, logger, err)
+				}
+				return
+			}
+
+			if stored != nil {
+				if stored.ContentType != "" {
+					w.Header().Set("Content-Type", stored.ContentType)
+				}
+
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(stored.Status)
+
+				_, err = w.Write(stored.Body)
+				logError(logger, err)
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			// the client may be gone, but the key must still be settled
+			ctx := context.WithoutCancel(r.Context())
+
+			if rec.status >= http.StatusInternalServerError {
+				logError(logger, is.Release(ctx, id, key))
+				return
+			}
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+
+			logError(logger, is.Save(ctx, id, key, &models.StoredResponse{
+				Status:      rec.status,
+				ContentType: w.Header().Get("Content-Type"),
+				Body:        rec.body.Bytes(),
+			}))
+		})
+	}
+}
+
+type IdempotencyPurger interface {
+	Purge(ctx context.Context) (int64, error)
+}
+
+// PurgeIdempotencyKeys removes expired idempotency keys, checking every
+// interval until ctx is done
+func PurgeIdempotencyKeys(ctx context.Context, logger *zap.Logger, ip IdempotencyPurger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := ip.Purge(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error("idempotency key purge failed", zap.Error(err))
+		}
+
+		if n > 0 {
+			logger.Info("idempotency keys purged", zap.Int64("keys", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
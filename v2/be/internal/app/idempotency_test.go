@@ -0,0 +1,209 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// echo answers with the request body, or with a server error when the body is "fail"
+func echo(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if string(body) == "fail" {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(body)
+}
+
+func TestIdempotent(t *testing.T) {
+	t.Run("stores first response", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"title":"read"}`))
+		r.Header.Set("Idempotency-Key", db.NewID())
+
+		im := testdata.NewIM()
+		h := app.Idempotent(zap.NewNop(), im)(http.HandlerFunc(echo))
+
+		session := scs.New()
+		m := lsm(t, session, db.NewID())
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.Equal(t, `{"title":"read"}`, rr.Body.String())
+
+		require.NotNil(t, im.Saved)
+		require.Equal(t, http.StatusCreated, im.Saved.Status)
+		require.Equal(t, "text/plain", im.Saved.ContentType)
+		require.Equal(t, `{"title":"read"}`, string(im.Saved.Body))
+	})
+
+	t.Run("replays stored response", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"title":"read"}`))
+		r.Header.Set("Idempotency-Key", "stored")
+
+		im := testdata.NewIM()
+		h := app.Idempotent(zap.NewNop(), im)(http.HandlerFunc(echo))
+
+		session := scs.New()
+		m := lsm(t, session, db.NewID())
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.Equal(t, `{"payload":"stored"}`, rr.Body.String())
+		require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		require.Equal(t, "true", rr.Header().Get("Idempotent-Replayed"))
+		require.Nil(t, im.Saved)
+	})
+
+	t.Run("releases key on server error", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString("fail"))
+		r.Header.Set("Idempotency-Key", db.NewID())
+
+		im := testdata.NewIM()
+		h := app.Idempotent(zap.NewNop(), im)(http.HandlerFunc(echo))
+
+		session := scs.New()
+		m := lsm(t, session, db.NewID())
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+		require.True(t, im.Released)
+		require.Nil(t, im.Saved)
+	})
+
+	t.Run("anonymous request", func(t *testing.T) {
+		t.Parallel()
+
+		key := db.NewID()
+		var keys []string
+
+		for _, body := range []string{`{"username":"ada"}`, `{"username":"bob"}`} {
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+			r.Header.Set("Idempotency-Key", key)
+
+			im := testdata.NewIM()
+			app.Idempotent(zap.NewNop(), im)(http.HandlerFunc(echo)).ServeHTTP(rr, r)
+
+			require.Equal(t, http.StatusCreated, rr.Code)
+			require.NotNil(t, im.Saved)
+			require.True(t, strings.HasPrefix(im.Key, key+":"))
+
+			keys = append(keys, im.Key)
+		}
+
+		require.NotEqual(t, keys[0], keys[1])
+	})
+
+	t.Run("passes through", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			method string
+			key    string
+		}{
+			{name: "no key", method: http.MethodPost},
+			{name: "not a post", method: http.MethodPatch, key: db.NewID()},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				rr := httptest.NewRecorder()
+				r := httptest.NewRequest(tt.method, "/", bytes.NewBufferString(`{"title":"read"}`))
+				r.Header.Set("Idempotency-Key", tt.key)
+
+				im := testdata.NewIM()
+				h := app.Idempotent(zap.NewNop(), im)(http.HandlerFunc(echo))
+
+				session := scs.New()
+				m := lsm(t, session, db.NewID())
+
+				session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+				require.Equal(t, http.StatusCreated, rr.Code)
+				require.Nil(t, im.Saved)
+			})
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			uid  string
+			key  string
+			code int
+		}{
+			{name: "long key", uid: db.NewID(), key: strings.Repeat("k", 256), code: http.StatusBadRequest},
+			{name: "reused key", uid: db.NewID(), key: "reused", code: http.StatusUnprocessableEntity},
+			{name: "key in flight", uid: db.NewID(), key: "busy", code: http.StatusConflict},
+			{name: "reserve error", uid: "25", key: db.NewID(), code: http.StatusInternalServerError},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				rr := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"title":"read"}`))
+				r.Header.Set("Idempotency-Key", tt.key)
+
+				im := testdata.NewIM()
+				h := app.Idempotent(zap.NewNop(), im)(http.HandlerFunc(echo))
+
+				session := scs.New()
+				m := lsm(t, session, tt.uid)
+
+				session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+				require.Equal(t, tt.code, rr.Code)
+				require.Contains(t, rr.Body.String(), "error")
+				require.Nil(t, im.Saved)
+			})
+		}
+	})
+}
+
+func TestPurgeIdempotencyKeys(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := &testdata.KeyPurger{Cancel: cancel}
+
+	done := make(chan struct{})
+	go func() {
+		app.PurgeIdempotencyKeys(ctx, zap.NewNop(), p, time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purge loop did not stop")
+	}
+
+	require.Equal(t, 2, p.Calls)
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"strings"
 
+	"v2/be/internal/models"
+
 	"github.com/alexedwards/scs/v2"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -20,8 +22,11 @@ type UserExister interface {
 const authenticatedUser = "authenticatedUser"
 
 var (
-	ErrUnauthorized = errors.New("must be an authenticated user")
-	userID          = CtxKey("userID")
+	ErrUnauthorized     = errors.New("must be an authenticated user")
+	ErrInsufficientRole = errors.New("your access to this task does not allow this")
+	ErrWorkspaceRole    = errors.New("your role in this workspace does not allow this")
+	userID              = CtxKey("userID")
+	ownerID             = CtxKey("ownerID")
 )
 
 // RequireAuthenticatedUser returns a function that satisfies the chi middleware pattern
@@ -66,3 +71,141 @@ func GetUserID(r *http.Request) string {
 func GetTaskID(r *http.Request) string {
 	return chi.URLParam(r, "task_id")
 }
+
+func GetBlockerID(r *http.Request) string {
+	return chi.URLParam(r, "blocker_id")
+}
+
+func GetCommentID(r *http.Request) string {
+	return chi.URLParam(r, "comment_id")
+}
+
+func GetAttachmentID(r *http.Request) string {
+	return chi.URLParam(r, "attachment_id")
+}
+
+func GetShareID(r *http.Request) string {
+	return chi.URLParam(r, "share_id")
+}
+
+func GetWorkspaceID(r *http.Request) string {
+	return chi.URLParam(r, "workspace_id")
+}
+
+func GetMemberID(r *http.Request) string {
+	return chi.URLParam(r, "member_id")
+}
+
+func GetTemplateID(r *http.Request) string {
+	return chi.URLParam(r, "template_id")
+}
+
+func GetTagID(r *http.Request) string {
+	return chi.URLParam(r, "tag_id")
+}
+
+func GetProjectID(r *http.Request) string {
+	return chi.URLParam(r, "project_id")
+}
+
+// GetOwnerID returns the owner of the task authorized by RequireTaskRole or
+// RequireAttachmentRole. The tasks model is scoped to the owner, so handlers
+// acting on shared tasks pass this id instead of the user's, while comments and
+// attachments check the user's own access. Without authorization it is the user itself.
+func GetOwnerID(r *http.Request) string {
+	if id, ok := r.Context().Value(ownerID).(string); ok {
+		return id
+	}
+
+	return GetUserID(r)
+}
+
+type TaskAuthorizer interface {
+	Task(ctx context.Context, taskID, userID string) (*models.Access, error)
+}
+
+// RequireTaskRole only lets requests through when the user has at least role on the task in the URL
+func RequireTaskRole(logger *zap.Logger, ta TaskAuthorizer, role models.Role) func(next http.Handler) http.Handler {
+	return requireRole(logger, role, func(r *http.Request) (*models.Access, error) {
+		return ta.Task(r.Context(), GetTaskID(r), GetUserID(r))
+	})
+}
+
+type AttachmentAuthorizer interface {
+	Attachment(ctx context.Context, id, userID string) (*models.Access, error)
+}
+
+// RequireAttachmentRole only lets requests through when the user has at least
+// role on the task of the attachment in the URL
+func RequireAttachmentRole(logger *zap.Logger, aa AttachmentAuthorizer, role models.Role) func(next http.Handler) http.Handler {
+	return requireRole(logger, role, func(r *http.Request) (*models.Access, error) {
+		return aa.Attachment(r.Context(), GetAttachmentID(r), GetUserID(r))
+	})
+}
+
+type WorkspaceAuthorizer interface {
+	Role(ctx context.Context, id, userID string) (models.WorkspaceRole, error)
+}
+
+// RequireWorkspaceRole only lets requests through when the user plays at least
+// role in the workspace in the URL
+func RequireWorkspaceRole(logger *zap.Logger, wa WorkspaceAuthorizer, role models.WorkspaceRole) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current, err := wa.Role(r.Context(), GetWorkspaceID(r), GetUserID(r))
+			if err != nil {
+				switch {
+				case errors.Is(err, models.ErrRecordNotFound):
+					MissingDataError(w, logger, err)
+				default:
+					ServerError(w, logger, err)
+				}
+				return
+			}
+
+			if !current.Allows(role) {
+				ForbiddenActionError(w, logger, ErrWorkspaceRole)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requireRole checks the access returned by resolve against role and records
+// the owner and the acting user in the request context. Under a workspace
+// route the task must also belong to that workspace.
+func requireRole(logger *zap.Logger, role models.Role, resolve func(r *http.Request) (*models.Access, error)) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			a, err := resolve(r)
+			if err != nil {
+				switch {
+				case errors.Is(err, models.ErrRecordNotFound):
+					MissingDataError(w, logger, err)
+				default:
+					ServerError(w, logger, err)
+				}
+				return
+			}
+
+			ws := GetWorkspaceID(r)
+			if ws != "" && (a.WorkspaceID == nil || *a.WorkspaceID != ws) {
+				MissingDataError(w, logger, models.ErrRecordNotFound)
+				return
+			}
+
+			if !a.Role.Allows(role) {
+				ForbiddenActionError(w, logger, ErrInsufficientRole)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), ownerID, a.OwnerID)
+			ctx = models.WithActor(ctx, GetUserID(r))
+			r = r.WithContext(ctx)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"v2/be/internal/app"
 	"v2/be/internal/app/testdata"
 	"v2/be/internal/db"
+	"v2/be/internal/models"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/go-chi/chi/v5"
@@ -180,3 +181,187 @@ func TestGetTaskID(t *testing.T) {
 
 	require.Equal(t, id, body)
 }
+
+func TestRequireTaskRole(t *testing.T) {
+	tests := []struct {
+		name  string
+		tid   string
+		role  models.Role
+		owner bool
+		code  int
+	}{
+		{name: "owner", tid: db.NewID(), role: models.RoleOwner, owner: true, code: http.StatusOK},
+		{name: "editor", tid: "editor", role: models.RoleEditor, code: http.StatusOK},
+		{name: "viewer reading", tid: "viewer", role: models.RoleViewer, code: http.StatusOK},
+		{name: "viewer editing", tid: "viewer", role: models.RoleEditor, code: http.StatusForbidden},
+		{name: "editor deleting", tid: "editor", role: models.RoleOwner, code: http.StatusForbidden},
+		{name: "assignee completing", tid: "assignee", role: models.RoleAssignee, code: http.StatusOK},
+		{name: "assignee editing", tid: "assignee", role: models.RoleEditor, code: http.StatusForbidden},
+		{name: "viewer completing", tid: "viewer", role: models.RoleAssignee, code: http.StatusForbidden},
+		{name: "no access", tid: "1", role: models.RoleViewer, code: http.StatusNotFound},
+		{name: "access error", tid: "25", role: models.RoleViewer, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uid := db.NewID()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(app.GetOwnerID(r)))
+			})
+
+			h := app.RequireTaskRole(zap.NewNop(), testdata.NewAC(), tt.role)(next)
+
+			session := scs.New()
+			m := lsm(t, session, uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusOK {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				body := readTestBody(t, rs.Body)
+				require.NotEmpty(t, body)
+				require.Equal(t, tt.owner, body == uid)
+			}
+		})
+	}
+}
+
+func TestRequireAttachmentRole(t *testing.T) {
+	tests := []struct {
+		name string
+		aid  string
+		role models.Role
+		code int
+	}{
+		{name: "viewer downloading", aid: "viewer", role: models.RoleViewer, code: http.StatusOK},
+		{name: "viewer deleting", aid: "viewer", role: models.RoleEditor, code: http.StatusForbidden},
+		{name: "missing attachment", aid: "1", role: models.RoleViewer, code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(setAttachmentID(t, tt.aid))
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("OK"))
+			})
+
+			h := app.RequireAttachmentRole(zap.NewNop(), testdata.NewAC(), tt.role)(next)
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestGetOwnerID(t *testing.T) {
+	t.Parallel()
+
+	id := db.NewID()
+
+	ctx := context.WithValue(context.Background(), userID, id)
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	r = r.WithContext(ctx)
+
+	require.Equal(t, id, app.GetOwnerID(r))
+}
+
+func TestRequireWorkspaceRole(t *testing.T) {
+	tests := []struct {
+		name string
+		wid  string
+		role models.WorkspaceRole
+		code int
+	}{
+		{name: "owner", wid: db.NewID(), role: models.WorkspaceOwner, code: http.StatusOK},
+		{name: "guest reading", wid: "guest", role: models.WorkspaceGuest, code: http.StatusOK},
+		{name: "guest creating", wid: "guest", role: models.WorkspaceMember, code: http.StatusForbidden},
+		{name: "member inviting", wid: "member", role: models.WorkspaceAdmin, code: http.StatusForbidden},
+		{name: "admin inviting", wid: "admin", role: models.WorkspaceAdmin, code: http.StatusOK},
+		{name: "not a member", wid: "1", role: models.WorkspaceGuest, code: http.StatusNotFound},
+		{name: "role error", wid: "25", role: models.WorkspaceGuest, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(setWorkspaceID(t, tt.wid))
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("OK"))
+			})
+
+			h := app.RequireWorkspaceRole(zap.NewNop(), testdata.NewWS(), tt.role)(next)
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestRequireTaskRoleInWorkspace(t *testing.T) {
+	tests := []struct {
+		name string
+		wid  string
+		tid  string
+		code int
+	}{
+		{name: "task of the workspace", wid: "ws", tid: "workspace", code: http.StatusOK},
+		{name: "task of another workspace", wid: "other", tid: "workspace", code: http.StatusNotFound},
+		{name: "personal task", wid: "ws", tid: db.NewID(), code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rtx := chi.NewRouteContext()
+			rtx.URLParams.Add("workspace_id", tt.wid)
+			rtx.URLParams.Add("task_id", tt.tid)
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(context.WithValue(context.Background(), chi.RouteCtxKey, rtx))
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("OK"))
+			})
+
+			h := app.RequireTaskRole(zap.NewNop(), testdata.NewAC(), models.RoleViewer)(next)
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
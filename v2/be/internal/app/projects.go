@@ -0,0 +1,266 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+	"v2/be/internal/validator"
+
+	"go.uber.org/zap"
+)
+
+var ErrInboxNotModified = errors.New("inbox cannot be renamed or deleted")
+
+type ProjectCreater interface {
+	Create(ctx context.Context, p *models.Project) error
+}
+
+func HandleCreateProject(logger *zap.Logger, pc ProjectCreater) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		var input struct {
+			Name string `json:"name"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.Name = parser.Sanitize(input.Name)
+
+		v := validator.New()
+		v.RequiredString(input.Name, "name", validator.Required)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		p := &models.Project{
+			ID:     db.NewID(),
+			UserID: id,
+			Name:   input.Name,
+		}
+
+		err = pc.Create(r.Context(), p)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrDuplicateProject):
+				DuplicateDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusCreated, parser.Envelope{"payload": p.ID})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type ProjectLister interface {
+	All(ctx context.Context, userID string) ([]*models.Project, error)
+}
+
+func HandleListProjects(logger *zap.Logger, pl ProjectLister) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		projects, err := pl.All(r.Context(), id)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		if projects == nil {
+			projects = []*models.Project{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": projects})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type ProjectGetter interface {
+	GetByID(ctx context.Context, id, userID string) (*models.Project, error)
+}
+
+func HandleGetProject(logger *zap.Logger, pg ProjectGetter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetProjectID(r)
+		userID := GetUserID(r)
+
+		p, err := pg.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": p})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+// HandleListProjectTasks lists the tasks of a single project, accepting the same query parameters as HandleListTasks
+func HandleListProjectTasks(logger *zap.Logger, pg ProjectGetter, tl TaskLister) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetProjectID(r)
+		userID := GetUserID(r)
+
+		v := validator.New()
+		f := readTaskFilter(r, v)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		p, err := pg.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		f.ProjectID = p.ID
+
+		tasks, next, err := tl.All(r.Context(), userID, f)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		if tasks == nil {
+			tasks = []*models.Task{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": tasks, "next_cursor": next})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type ProjectUpdater interface {
+	ProjectGetter
+	Update(ctx context.Context, p *models.Project) error
+}
+
+func HandleUpdateProject(logger *zap.Logger, pu ProjectUpdater) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetProjectID(r)
+		userID := GetUserID(r)
+
+		var input struct {
+			Name string `json:"name"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.Name = parser.Sanitize(input.Name)
+
+		v := validator.New()
+		v.RequiredString(input.Name, "name", validator.Required)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		p, err := pu.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		if p.Inbox {
+			ForbiddenActionError(w, logger, ErrInboxNotModified)
+			return
+		}
+
+		p.Name = input.Name
+
+		err = pu.Update(r.Context(), p)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrDuplicateProject):
+				DuplicateDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": p.ID})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type ProjectDeleter interface {
+	ProjectGetter
+	Delete(ctx context.Context, id, userID string) error
+}
+
+func HandleDeleteProject(logger *zap.Logger, pd ProjectDeleter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetProjectID(r)
+		userID := GetUserID(r)
+
+		p, err := pd.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		if p.Inbox {
+			ForbiddenActionError(w, logger, ErrInboxNotModified)
+			return
+		}
+
+		err = pd.Delete(r.Context(), p.ID, userID)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": "deleted"})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
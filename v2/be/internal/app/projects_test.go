@@ -0,0 +1,362 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setProjectID(t *testing.T, id string) context.Context {
+	t.Helper()
+
+	rtx := chi.NewRouteContext()
+	rtx.URLParams.Add("project_id", id)
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rtx)
+
+	return ctx
+}
+
+func TestHandleCreateProject(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{
+			name: "valid",
+			body: `{"name": "work"}`,
+			code: http.StatusCreated,
+		},
+		{
+			name: "bad body",
+			body: `{"title": "work"}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "invalid data",
+			body: `{"name": ""}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "duplicate data",
+			body: `{"name": "test"}`,
+			code: http.StatusConflict,
+		},
+		{
+			name: "op failed",
+			body: `{"name": "testX"}`,
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer([]byte(tt.body)))
+
+			session := scs.New()
+			h := app.HandleCreateProject(zap.NewNop(), testdata.NewPM())
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+			require.Equal(t, tt.code, rr.Code)
+			require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestHandleListProjects(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		code   int
+		expect string
+	}{
+		{
+			name:   "valid",
+			userID: db.NewID(),
+			code:   http.StatusOK,
+			expect: "Inbox",
+		},
+		{
+			name:   "empty",
+			userID: "1",
+			code:   http.StatusOK,
+			expect: "[]",
+		},
+		{
+			name:   "op failed",
+			userID: "25",
+			code:   http.StatusInternalServerError,
+			expect: "error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			session := scs.New()
+			h := app.HandleListProjects(zap.NewNop(), testdata.NewPM())
+			m := lsm(t, session, tt.userID)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+			require.Equal(t, tt.code, rr.Code)
+
+			rs := rr.Result()
+			defer rs.Body.Close()
+
+			body := readTestBody(t, rs.Body)
+
+			require.Contains(t, body, tt.expect)
+		})
+	}
+}
+
+func TestHandleGetProject(t *testing.T) {
+	tests := []struct {
+		name string
+		pid  string
+		code int
+	}{
+		{
+			name: "valid",
+			pid:  db.NewID(),
+			code: http.StatusOK,
+		},
+		{
+			name: "missing project",
+			pid:  "1",
+			code: http.StatusNotFound,
+		},
+		{
+			name: "op failed",
+			pid:  "25",
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(setProjectID(t, tt.pid))
+
+			session := scs.New()
+			h := app.HandleGetProject(zap.NewNop(), testdata.NewPM())
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleListProjectTasks(t *testing.T) {
+	tests := []struct {
+		name   string
+		pid    string
+		userID string
+		query  string
+		code   int
+	}{
+		{
+			name:   "valid",
+			pid:    db.NewID(),
+			userID: db.NewID(),
+			code:   http.StatusOK,
+		},
+		{
+			name:   "invalid params",
+			pid:    db.NewID(),
+			userID: db.NewID(),
+			query:  "?limit=0",
+			code:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "missing project",
+			pid:    "1",
+			userID: db.NewID(),
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "project op failed",
+			pid:    "25",
+			userID: db.NewID(),
+			code:   http.StatusInternalServerError,
+		},
+		{
+			name:   "tasks op failed",
+			pid:    db.NewID(),
+			userID: "25",
+			code:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			r = r.WithContext(setProjectID(t, tt.pid))
+
+			session := scs.New()
+			h := app.HandleListProjectTasks(zap.NewNop(), testdata.NewPM(), testdata.NewTM())
+			m := lsm(t, session, tt.userID)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleUpdateProject(t *testing.T) {
+	tests := []struct {
+		name string
+		pid  string
+		body string
+		code int
+	}{
+		{
+			name: "valid",
+			pid:  db.NewID(),
+			body: `{"name": "home"}`,
+			code: http.StatusOK,
+		},
+		{
+			name: "bad body",
+			pid:  db.NewID(),
+			body: `{"title": "home"}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "invalid data",
+			pid:  db.NewID(),
+			body: `{"name": ""}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "missing project",
+			pid:  "1",
+			body: `{"name": "home"}`,
+			code: http.StatusNotFound,
+		},
+		{
+			name: "get failed",
+			pid:  "25",
+			body: `{"name": "home"}`,
+			code: http.StatusInternalServerError,
+		},
+		{
+			name: "inbox",
+			pid:  "345",
+			body: `{"name": "home"}`,
+			code: http.StatusForbidden,
+		},
+		{
+			name: "duplicate data",
+			pid:  db.NewID(),
+			body: `{"name": "test"}`,
+			code: http.StatusConflict,
+		},
+		{
+			name: "update failed",
+			pid:  db.NewID(),
+			body: `{"name": "testX"}`,
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/", bytes.NewBuffer([]byte(tt.body)))
+			r = r.WithContext(setProjectID(t, tt.pid))
+
+			session := scs.New()
+			h := app.HandleUpdateProject(zap.NewNop(), testdata.NewPM())
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleDeleteProject(t *testing.T) {
+	tests := []struct {
+		name string
+		pid  string
+		code int
+	}{
+		{
+			name: "valid",
+			pid:  db.NewID(),
+			code: http.StatusOK,
+		},
+		{
+			name: "missing project",
+			pid:  "1",
+			code: http.StatusNotFound,
+		},
+		{
+			name: "get failed",
+			pid:  "25",
+			code: http.StatusInternalServerError,
+		},
+		{
+			name: "inbox",
+			pid:  "345",
+			code: http.StatusForbidden,
+		},
+		{
+			name: "delete failed",
+			pid:  "201",
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			r = r.WithContext(setProjectID(t, tt.pid))
+
+			session := scs.New()
+			h := app.HandleDeleteProject(zap.NewNop(), testdata.NewPM())
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
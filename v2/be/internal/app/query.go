@@ -0,0 +1,52 @@
+package app
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"v2/be/internal/validator"
+)
+
+// readString returns the trimmed value of key or fallback when it is absent
+func readString(qs url.Values, key, fallback string) string {
+	s := strings.TrimSpace(qs.Get(key))
+	if s == "" {
+		return fallback
+	}
+
+	return s
+}
+
+// readInt returns the integer value of key, recording a validation error when it is malformed
+func readInt(qs url.Values, key string, fallback int, v *validator.Validator) int {
+	s := strings.TrimSpace(qs.Get(key))
+	if s == "" {
+		return fallback
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		v.AddError(key, "must be an integer value")
+		return fallback
+	}
+
+	return i
+}
+
+// readBool returns the boolean value of key or nil when it is absent,
+// recording a validation error when it is malformed
+func readBool(qs url.Values, key string, v *validator.Validator) *bool {
+	s := strings.TrimSpace(qs.Get(key))
+	if s == "" {
+		return nil
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		v.AddError(key, "must be true or false")
+		return nil
+	}
+
+	return &b
+}
@@ -14,24 +14,123 @@ func Routes(
 	logger *zap.Logger,
 	u *models.UsersModel,
 	t *models.TasksModel,
+	tg *models.TagsModel,
+	p *models.ProjectsModel,
+	wf *models.WorkflowsModel,
+	c *models.CommentsModel,
+	a *models.AttachmentsModel,
+	sh *models.SharesModel,
+	ac *models.AccessModel,
+	ws *models.WorkspacesModel,
+	tp *models.TemplatesModel,
+	im *models.IdempotencyModel,
 ) http.Handler {
 	router := chi.NewRouter()
 	router.Use(sessions.LoadAndSave)
 
+	idempotent := Idempotent(logger, im)
+
 	router.Get("/", HandleHealthz())
-	router.Post("/signup", HandleSignup(logger, sessions, u))
+	router.With(idempotent).Post("/signup", HandleSignup(logger, sessions, u, p))
 	router.Post("/login", HandleLogin(logger, sessions, u))
 
 	router.Group(func(r chi.Router) {
 		r.Use(RequireAuthenticatedUser(logger, sessions, u))
+		r.Use(idempotent)
 		r.Post("/logout", HandleLogout(logger, sessions))
 
 		r.Post("/tasks/create", HandleCreateTask(logger, t))
 		r.Get("/tasks", HandleListTasks(logger, t))
-		r.Get("/tasks/{task_id}", HandleGetTask(logger, t))
-		r.Patch("/tasks/{task_id}/update", HandleUpdateTask(logger, t))
-		r.Patch("/tasks/{task_id}/complete", HandleCompleteTask(logger, t))
-		r.Delete("/tasks/{task_id}", HandleDeleteTask(logger, t))
+		r.Get("/tasks/search", HandleSearchTasks(logger, t))
+		r.Get("/tasks/archived", HandleListArchivedTasks(logger, t))
+		r.Post("/tasks/archive-completed", HandleArchiveCompleted(logger, t))
+		r.Post("/tasks/bulk", HandleBulkTasks(logger, t))
+
+		viewer := RequireTaskRole(logger, ac, models.RoleViewer)
+		assignee := RequireTaskRole(logger, ac, models.RoleAssignee)
+		editor := RequireTaskRole(logger, ac, models.RoleEditor)
+		owner := RequireTaskRole(logger, ac, models.RoleOwner)
+
+		r.With(viewer).Get("/tasks/{task_id}", HandleGetTask(logger, t))
+		r.With(editor).Patch("/tasks/{task_id}/update", HandleUpdateTask(logger, t))
+		r.With(assignee).Patch("/tasks/{task_id}/complete", HandleCompleteTask(logger, t))
+		r.With(editor).Patch("/tasks/{task_id}/reopen", HandleReopenTask(logger, t))
+		r.With(editor).Patch("/tasks/{task_id}/status", HandleSetTaskStatus(logger, t))
+		r.With(editor).Patch("/tasks/{task_id}/assign", HandleAssignTask(logger, t))
+		r.With(editor).Post("/tasks/{task_id}/archive", HandleArchiveTask(logger, t))
+		r.With(owner).Patch("/tasks/{task_id}/move", HandleMoveTask(logger, t))
+		r.With(editor).Post("/tasks/{task_id}/blockers", HandleAddBlocker(logger, t))
+		r.With(editor).Delete("/tasks/{task_id}/blockers/{blocker_id}", HandleRemoveBlocker(logger, t))
+		r.With(owner).Delete("/tasks/{task_id}", HandleDeleteTask(logger, t))
+		r.Post("/tasks/{task_id}/restore", HandleRestoreTask(logger, t))
+		r.With(viewer).Get("/tasks/{task_id}/history", HandleTaskHistory(logger, t))
+		r.With(viewer).Get("/tasks/{task_id}/comments", HandleListComments(logger, t, c))
+		r.With(editor).Post("/tasks/{task_id}/comments", HandleCreateComment(logger, c))
+		r.With(viewer).Get("/tasks/{task_id}/attachments", HandleListAttachments(logger, t, a))
+		r.With(editor).Post("/tasks/{task_id}/attachments", HandleUploadAttachment(logger, a))
+
+		r.Patch("/comments/{comment_id}", HandleUpdateComment(logger, c))
+		r.Delete("/comments/{comment_id}", HandleDeleteComment(logger, c))
+
+		r.With(RequireAttachmentRole(logger, ac, models.RoleViewer)).Get("/attachments/{attachment_id}", HandleDownloadAttachment(logger, a))
+		r.With(RequireAttachmentRole(logger, ac, models.RoleEditor)).Delete("/attachments/{attachment_id}", HandleDeleteAttachment(logger, a))
+
+		r.Post("/shares", HandleCreateShare(logger, sh))
+		r.Get("/shares", HandleListShares(logger, sh))
+		r.Delete("/shares/{share_id}", HandleDeleteShare(logger, sh))
+
+		r.Post("/workspaces", HandleCreateWorkspace(logger, ws))
+		r.Get("/workspaces", HandleListWorkspaces(logger, ws))
+
+		r.Route("/w/{workspace_id}", func(r chi.Router) {
+			r.Use(RequireWorkspaceRole(logger, ws, models.WorkspaceGuest))
+
+			member := RequireWorkspaceRole(logger, ws, models.WorkspaceMember)
+			admin := RequireWorkspaceRole(logger, ws, models.WorkspaceAdmin)
+
+			r.With(RequireWorkspaceRole(logger, ws, models.WorkspaceOwner)).Delete("/", HandleDeleteWorkspace(logger, ws))
+
+			r.Get("/members", HandleListMembers(logger, ws))
+			r.With(admin).Post("/members", HandleInviteMember(logger, ws))
+			r.With(admin).Delete("/members/{member_id}", HandleRemoveMember(logger, ws))
+
+			r.Get("/tasks", HandleListTasks(logger, t))
+			r.With(member).Post("/tasks", HandleCreateTask(logger, t))
+			r.With(viewer).Get("/tasks/{task_id}", HandleGetTask(logger, t))
+			r.With(editor).Patch("/tasks/{task_id}/update", HandleUpdateTask(logger, t))
+			r.With(assignee).Patch("/tasks/{task_id}/complete", HandleCompleteTask(logger, t))
+			r.With(editor).Patch("/tasks/{task_id}/reopen", HandleReopenTask(logger, t))
+			r.With(editor).Patch("/tasks/{task_id}/assign", HandleAssignTask(logger, t))
+			r.With(owner).Delete("/tasks/{task_id}", HandleDeleteTask(logger, t))
+		})
+
+		r.Get("/sync", HandleGetSync(logger, t))
+		r.Post("/sync", HandlePostSync(logger, t))
+
+		r.Get("/trash", HandleListTrash(logger, t))
+		r.Delete("/trash", HandleEmptyTrash(logger, t))
+
+		r.Post("/templates/create", HandleCreateTemplate(logger, tp))
+		r.Get("/templates", HandleListTemplates(logger, tp))
+		r.Get("/templates/{template_id}", HandleGetTemplate(logger, tp))
+		r.Delete("/templates/{template_id}", HandleDeleteTemplate(logger, tp))
+		r.Post("/templates/{template_id}/instantiate", HandleInstantiateTemplate(logger, tp))
+
+		r.Post("/tags/create", HandleCreateTag(logger, tg))
+		r.Get("/tags", HandleListTags(logger, tg))
+		r.Get("/tags/{tag_id}", HandleGetTag(logger, tg))
+		r.Patch("/tags/{tag_id}/update", HandleUpdateTag(logger, tg))
+		r.Delete("/tags/{tag_id}", HandleDeleteTag(logger, tg))
+
+		r.Post("/projects/create", HandleCreateProject(logger, p))
+		r.Get("/projects", HandleListProjects(logger, p))
+		r.Get("/projects/{project_id}", HandleGetProject(logger, p))
+		r.Get("/projects/{project_id}/tasks", HandleListProjectTasks(logger, p, t))
+		r.Patch("/projects/{project_id}/update", HandleUpdateProject(logger, p))
+		r.Delete("/projects/{project_id}", HandleDeleteProject(logger, p))
+
+		r.Get("/workflow", HandleGetWorkflow(logger, wf))
+		r.Put("/workflow", HandleUpdateWorkflow(logger, wf))
 	})
 	return router
 }
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+	"v2/be/internal/validator"
+
+	"go.uber.org/zap"
+)
+
+type ShareCreater interface {
+	Create(ctx context.Context, s *models.Share) error
+}
+
+// HandleCreateShare grants another user viewer or editor access to one of the
+// user's tasks, or to every task of one of their projects
+func HandleCreateShare(logger *zap.Logger, sc ShareCreater) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		var input struct {
+			Username  string `json:"username"`
+			TaskID    string `json:"task_id"`
+			ProjectID string `json:"project_id"`
+			Role      string `json:"role"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.Username = strings.TrimSpace(input.Username)
+		input.TaskID = strings.TrimSpace(input.TaskID)
+		input.ProjectID = strings.TrimSpace(input.ProjectID)
+
+		v := validator.New()
+		v.RequiredString(input.Username, "username", validator.Required)
+		v.Check((input.TaskID == "") != (input.ProjectID == ""), "target", "exactly one of task_id and project_id is required")
+		v.Check(validator.PermittedValue(input.Role, models.ShareRoles...), "role", "invalid role value")
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		s := &models.Share{
+			ID:       db.NewID(),
+			OwnerID:  id,
+			Username: input.Username,
+			Role:     models.Role(input.Role),
+		}
+
+		if input.TaskID != "" {
+			s.TaskID = &input.TaskID
+		} else {
+			s.ProjectID = &input.ProjectID
+		}
+
+		err = sc.Create(r.Context(), s)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrShareUserNotFound), errors.Is(err, models.ErrShareWithSelf):
+				InvalidDataError(w, map[string]string{"username": err.Error()})
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			case errors.Is(err, models.ErrDuplicateShare):
+				DuplicateDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusCreated, parser.Envelope{"payload": s})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type ShareLister interface {
+	All(ctx context.Context, userID string) ([]*models.Share, error)
+}
+
+// HandleListShares returns the shares the user granted and received
+func HandleListShares(logger *zap.Logger, sl ShareLister) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		shares, err := sl.All(r.Context(), id)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		if shares == nil {
+			shares = []*models.Share{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": shares})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type ShareDeleter interface {
+	Delete(ctx context.Context, id, userID string) error
+}
+
+// HandleDeleteShare lets the owner revoke a share and the recipient leave it
+func HandleDeleteShare(logger *zap.Logger, sd ShareDeleter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetShareID(r)
+		userID := GetUserID(r)
+
+		err := sd.Delete(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": "deleted"})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
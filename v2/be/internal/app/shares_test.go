@@ -0,0 +1,134 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setShareID(t *testing.T, id string) context.Context {
+	t.Helper()
+
+	rtx := chi.NewRouteContext()
+	rtx.URLParams.Add("share_id", id)
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rtx)
+
+	return ctx
+}
+
+func TestHandleCreateShare(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{name: "task", body: `{"username": "ada", "task_id": "7", "role": "viewer"}`, code: http.StatusCreated},
+		{name: "project", body: `{"username": "ada", "project_id": "7", "role": "editor"}`, code: http.StatusCreated},
+		{name: "bad body", body: `{"user": "ada"}`, code: http.StatusBadRequest},
+		{name: "no username", body: `{"username": " ", "task_id": "7", "role": "viewer"}`, code: http.StatusUnprocessableEntity},
+		{name: "no target", body: `{"username": "ada", "role": "viewer"}`, code: http.StatusUnprocessableEntity},
+		{name: "two targets", body: `{"username": "ada", "task_id": "7", "project_id": "7", "role": "viewer"}`, code: http.StatusUnprocessableEntity},
+		{name: "owner role", body: `{"username": "ada", "task_id": "7", "role": "owner"}`, code: http.StatusUnprocessableEntity},
+		{name: "unknown user", body: `{"username": "nobody", "task_id": "7", "role": "viewer"}`, code: http.StatusUnprocessableEntity},
+		{name: "self", body: `{"username": "me", "task_id": "7", "role": "viewer"}`, code: http.StatusUnprocessableEntity},
+		{name: "missing task", body: `{"username": "ada", "task_id": "1", "role": "viewer"}`, code: http.StatusNotFound},
+		{name: "duplicate", body: `{"username": "taken", "task_id": "7", "role": "viewer"}`, code: http.StatusConflict},
+		{name: "create error", body: `{"username": "broken", "task_id": "7", "role": "viewer"}`, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.body))
+
+			h := app.HandleCreateShare(zap.NewNop(), testdata.NewSM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleListShares(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		want string
+		code int
+	}{
+		{name: "valid", uid: db.NewID(), want: `"role":"viewer"`, code: http.StatusOK},
+		{name: "no shares", uid: "204", want: `"payload":[]`, code: http.StatusOK},
+		{name: "list error", uid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			h := app.HandleListShares(zap.NewNop(), testdata.NewSM())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusOK {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleDeleteShare(t *testing.T) {
+	tests := []struct {
+		name string
+		sid  string
+		code int
+	}{
+		{name: "valid", sid: db.NewID(), code: http.StatusOK},
+		{name: "missing share", sid: "1", code: http.StatusNotFound},
+		{name: "delete error", sid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			r = r.WithContext(setShareID(t, tt.sid))
+
+			h := app.HandleDeleteShare(zap.NewNop(), testdata.NewSM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+	"v2/be/internal/validator"
+
+	"go.uber.org/zap"
+)
+
+type TaskChangesLister interface {
+	Changes(ctx context.Context, userID string, since *models.SyncToken) (*models.SyncChanges, error)
+}
+
+// HandleGetSync returns the user's tasks written since the since token, with
+// tombstones for those deleted, and the token to pass on the next call.
+// Without a token every task is returned.
+func HandleGetSync(logger *zap.Logger, tc TaskChangesLister) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		var since *models.SyncToken
+
+		if s := readString(r.URL.Query(), "since", ""); s != "" {
+			token, err := models.DecodeSyncToken(s)
+			if err != nil {
+				InvalidDataError(w, map[string]string{"since": err.Error()})
+				return
+			}
+
+			since = token
+		}
+
+		changes, err := tc.Changes(r.Context(), id, since)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": changes})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+// syncTask holds the fields of a task changed by a client
+type syncTask struct {
+	taskPatch
+	Completed parser.Field[bool] `json:"completed"`
+}
+
+// syncInput is one change of a sync request
+type syncInput struct {
+	Op     string   `json:"op"`
+	TaskID string   `json:"task_id"`
+	Task   syncTask `json:"task"`
+}
+
+// syncResult is the outcome of one change of a sync request, with the status
+// code the route of the change would have answered
+type syncResult struct {
+	TaskID    string   `json:"task_id"`
+	Op        string   `json:"op"`
+	Status    int      `json:"status"`
+	Conflicts []string `json:"conflicts,omitempty"`
+	Error     any      `json:"error,omitempty"`
+}
+
+// readSyncChange validates in and turns it into the change applied by the model
+func readSyncChange(in *syncInput, v *validator.Validator) models.SyncChange {
+	in.TaskID = strings.TrimSpace(in.TaskID)
+
+	v.RequiredString(in.TaskID, "task_id", validator.Required)
+	v.Check(validator.PermittedValue(in.Op, models.SyncOps...), "op", "invalid op value")
+
+	c := models.SyncChange{Op: in.Op, TaskID: in.TaskID}
+	if in.Op != models.SyncUpsert {
+		return c
+	}
+
+	c.Task = &models.Task{}
+	for _, change := range in.Task.changes(v) {
+		change(c.Task)
+	}
+
+	c.Fields = in.Task.fields()
+
+	if in.Task.Completed.Set {
+		v.Check(!in.Task.Completed.Null, "completed", "must not be null")
+
+		c.Task.Completed = in.Task.Completed.Value
+		c.Fields = append(c.Fields, "completed")
+	}
+
+	v.Check(len(c.Fields) > 0, "task", "must change at least one field")
+
+	return c
+}
+
+// syncStatus returns the status code and error the route of a change would
+// have answered with err
+func syncStatus(logger *zap.Logger, err error) (int, any) {
+	switch {
+	case errors.Is(err, models.ErrTaskGone):
+		return http.StatusGone, err.Error()
+	case errors.Is(err, models.ErrIncompleteTask), errors.Is(err, models.ErrReminderAfterDue):
+		return http.StatusUnprocessableEntity, err.Error()
+	default:
+		return bulkStatus(logger, err)
+	}
+}
+
+type TaskSyncer interface {
+	Sync(ctx context.Context, userID string, base *models.SyncToken, changes []models.SyncChange) ([]*models.SyncResult, error)
+}
+
+// HandlePostSync applies a batch of changes made by a client and returns the
+// result of each. Every change only writes the fields it holds and wins over
+// the server's values, which are reported as conflicts when they changed
+// after the base token the client last synced with. Clients should fetch a
+// new token once the batch is applied. Invalid changes are reported without
+// being applied and do not stop the others.
+func HandlePostSync(logger *zap.Logger, ts TaskSyncer) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		var input struct {
+			Base    string      `json:"base"`
+			Changes []syncInput `json:"changes"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		v := validator.New()
+		v.Check(len(input.Changes) > 0, "changes", "must contain at least one change")
+		v.Check(len(input.Changes) <= models.MaxSyncChanges, "changes", "must not contain more than 100 changes")
+
+		var base *models.SyncToken
+		if input.Base != "" {
+			base, err = models.DecodeSyncToken(input.Base)
+			if err != nil {
+				v.AddError("base", err.Error())
+			}
+		}
+
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		results := make([]*syncResult, len(input.Changes))
+
+		var changes []models.SyncChange
+		var pending []int
+
+		for i := range input.Changes {
+			in := &input.Changes[i]
+
+			cv := validator.New()
+			c := readSyncChange(in, cv)
+
+			results[i] = &syncResult{TaskID: in.TaskID, Op: in.Op}
+			if !cv.Valid() {
+				results[i].Status, results[i].Error = http.StatusUnprocessableEntity, cv.Errors()
+				continue
+			}
+
+			changes = append(changes, c)
+			pending = append(pending, i)
+		}
+
+		if len(changes) > 0 {
+			applied, serr := ts.Sync(r.Context(), id, base, changes)
+			if serr != nil {
+				ServerError(w, logger, serr)
+				return
+			}
+
+			for j, res := range applied {
+				results[pending[j]].Status, results[pending[j]].Error = syncStatus(logger, res.Err)
+				results[pending[j]].Conflicts = res.Conflicts
+			}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": results})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
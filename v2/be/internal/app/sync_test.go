@@ -0,0 +1,168 @@
+package app_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandleGetSync(t *testing.T) {
+	token := (&models.SyncToken{XID: 7, Time: time.Now()}).Encode()
+
+	tests := []struct {
+		name    string
+		uid     string
+		query   string
+		code    int
+		deleted int
+	}{
+		{name: "first sync", uid: db.NewID(), code: http.StatusOK},
+		{name: "since token", uid: db.NewID(), query: "?since=" + token, code: http.StatusOK, deleted: 1},
+		{name: "invalid token", uid: db.NewID(), query: "?since=later", code: http.StatusUnprocessableEntity},
+		{name: "changes error", uid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+
+			h := app.HandleGetSync(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code != http.StatusOK {
+				return
+			}
+
+			var out struct {
+				Payload struct {
+					Tasks   []*models.Task      `json:"tasks"`
+					Deleted []*models.Tombstone `json:"deleted"`
+					Token   string              `json:"token"`
+				} `json:"payload"`
+			}
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&out))
+
+			require.Len(t, out.Payload.Tasks, 1)
+			require.Len(t, out.Payload.Deleted, tt.deleted)
+
+			_, err := models.DecodeSyncToken(out.Payload.Token)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestHandlePostSync(t *testing.T) {
+	t.Run("results", func(t *testing.T) {
+		t.Parallel()
+
+		id := db.NewID()
+		token := (&models.SyncToken{XID: 7, Time: time.Now()}).Encode()
+		body := `{"base": "` + token + `", "changes": [
+			{"op": "upsert", "task_id": "` + id + `", "task": {"title": "Read", "description": "A chapter"}},
+			{"op": "upsert", "task_id": "409", "task": {"title": "Write", "priority": "high"}},
+			{"op": "delete", "task_id": "1"},
+			{"op": "upsert", "task_id": "410", "task": {"completed": true}},
+			{"op": "upsert", "task_id": "422", "task": {"due_at": null}},
+			{"op": "upsert", "task_id": "` + id + `", "task": {}},
+			{"op": "upsert", "task_id": "` + id + `", "task": {"title": null}},
+			{"op": "move", "task_id": "` + id + `"}
+		]}`
+
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+
+		h := app.HandlePostSync(zap.NewNop(), testdata.NewTM())
+
+		session := scs.New()
+		m := lsm(t, session, db.NewID())
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var out struct {
+			Payload []struct {
+				TaskID    string   `json:"task_id"`
+				Status    int      `json:"status"`
+				Conflicts []string `json:"conflicts"`
+			} `json:"payload"`
+		}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&out))
+
+		var statuses []int
+		for _, p := range out.Payload {
+			statuses = append(statuses, p.Status)
+		}
+
+		require.Equal(t, []int{
+			http.StatusOK,
+			http.StatusOK,
+			http.StatusNotFound,
+			http.StatusGone,
+			http.StatusUnprocessableEntity,
+			http.StatusUnprocessableEntity,
+			http.StatusUnprocessableEntity,
+			http.StatusUnprocessableEntity,
+		}, statuses)
+		require.Equal(t, []string{"title", "priority"}, out.Payload[1].Conflicts)
+		require.Empty(t, out.Payload[0].Conflicts)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		many := make([]string, 101)
+		for i := range many {
+			many[i] = `{"op": "delete", "task_id": "7"}`
+		}
+
+		tests := []struct {
+			name string
+			uid  string
+			body string
+			code int
+		}{
+			{name: "bad body", uid: db.NewID(), body: `{"ops": []}`, code: http.StatusBadRequest},
+			{name: "unknown field", uid: db.NewID(), body: `{"changes": [{"op": "upsert", "task_id": "7", "task": {"name": "x"}}]}`, code: http.StatusBadRequest},
+			{name: "no changes", uid: db.NewID(), body: `{"changes": []}`, code: http.StatusUnprocessableEntity},
+			{name: "too many changes", uid: db.NewID(), body: `{"changes": [` + strings.Join(many, ",") + `]}`, code: http.StatusUnprocessableEntity},
+			{name: "invalid base", uid: db.NewID(), body: `{"base": "yesterday", "changes": [{"op": "delete", "task_id": "7"}]}`, code: http.StatusUnprocessableEntity},
+			{name: "sync error", uid: "25", body: `{"changes": [{"op": "delete", "task_id": "7"}]}`, code: http.StatusInternalServerError},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				rr := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.body))
+
+				h := app.HandlePostSync(zap.NewNop(), testdata.NewTM())
+
+				session := scs.New()
+				m := lsm(t, session, tt.uid)
+
+				session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+				require.Equal(t, tt.code, rr.Code)
+			})
+		}
+	})
+}
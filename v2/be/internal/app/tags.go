@@ -0,0 +1,232 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+	"v2/be/internal/validator"
+
+	"go.uber.org/zap"
+)
+
+// cleanTags sanitizes and de-duplicates tag names, recording empty ones in v.
+// A nil slice stays nil so callers can tell an absent list from an empty one.
+func cleanTags(names []string, v *validator.Validator) []string {
+	if names == nil {
+		return nil
+	}
+
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		name = parser.Sanitize(name)
+		v.Check(name != "", "tags", "must not contain empty names")
+
+		if name != "" && !slices.Contains(tags, name) {
+			tags = append(tags, name)
+		}
+	}
+
+	return tags
+}
+
+type TagCreater interface {
+	Create(ctx context.Context, t *models.Tag) error
+}
+
+func HandleCreateTag(logger *zap.Logger, tc TagCreater) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		var input struct {
+			Name string `json:"name"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.Name = parser.Sanitize(input.Name)
+
+		v := validator.New()
+		v.RequiredString(input.Name, "name", validator.Required)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		t := &models.Tag{
+			ID:     db.NewID(),
+			UserID: id,
+			Name:   input.Name,
+		}
+
+		err = tc.Create(r.Context(), t)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrDuplicateTag):
+				DuplicateDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusCreated, parser.Envelope{"payload": t.ID})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TagLister interface {
+	All(ctx context.Context, userID string) ([]*models.Tag, error)
+}
+
+func HandleListTags(logger *zap.Logger, tl TagLister) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		tags, err := tl.All(r.Context(), id)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		if tags == nil {
+			tags = []*models.Tag{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": tags})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TagGetter interface {
+	GetByID(ctx context.Context, id, userID string) (*models.Tag, error)
+}
+
+func HandleGetTag(logger *zap.Logger, tg TagGetter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTagID(r)
+		userID := GetUserID(r)
+
+		t, err := tg.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TagUpdater interface {
+	TagGetter
+	Update(ctx context.Context, t *models.Tag) error
+}
+
+func HandleUpdateTag(logger *zap.Logger, tu TagUpdater) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTagID(r)
+		userID := GetUserID(r)
+
+		var input struct {
+			Name string `json:"name"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.Name = parser.Sanitize(input.Name)
+
+		v := validator.New()
+		v.RequiredString(input.Name, "name", validator.Required)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		t, err := tu.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		t.Name = input.Name
+
+		err = tu.Update(r.Context(), t)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrDuplicateTag):
+				DuplicateDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t.ID})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TagDeleter interface {
+	TagGetter
+	Delete(ctx context.Context, id, userID string) error
+}
+
+func HandleDeleteTag(logger *zap.Logger, td TagDeleter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTagID(r)
+		userID := GetUserID(r)
+
+		t, err := td.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = td.Delete(r.Context(), t.ID, userID)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": "deleted"})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
@@ -0,0 +1,319 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setTagID(t *testing.T, id string) context.Context {
+	t.Helper()
+
+	rtx := chi.NewRouteContext()
+	rtx.URLParams.Add("tag_id", id)
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rtx)
+
+	return ctx
+}
+
+func TestHandleCreateTag(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer([]byte(`{"name": "work"}`)))
+
+		session := scs.New()
+
+		h := app.HandleCreateTag(zap.NewNop(), testdata.NewTG())
+		m := lsm(t, session, db.NewID())
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		rs := rr.Result()
+		defer rs.Body.Close()
+
+		body := readTestBody(t, rs.Body)
+
+		require.Contains(t, body, "payload")
+		require.Equal(t, "application/json", rs.Header.Get("Content-Type"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			body string
+			code int
+		}{
+			{
+				name: "bad body",
+				body: `{"title": "work"}`,
+				code: http.StatusBadRequest,
+			},
+			{
+				name: "invalid data",
+				body: `{"name": " "}`,
+				code: http.StatusUnprocessableEntity,
+			},
+			{
+				name: "duplicate data",
+				body: `{"name": "test"}`,
+				code: http.StatusConflict,
+			},
+			{
+				name: "op failed",
+				body: `{"name": "testX"}`,
+				code: http.StatusInternalServerError,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				rr := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer([]byte(tt.body)))
+
+				session := scs.New()
+
+				h := app.HandleCreateTag(zap.NewNop(), testdata.NewTG())
+				m := lsm(t, session, db.NewID())
+
+				session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+				require.Equal(t, tt.code, rr.Code)
+
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				body := readTestBody(t, rs.Body)
+
+				require.Contains(t, body, "error")
+			})
+		}
+	})
+}
+
+func TestHandleListTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		code   int
+		expect string
+	}{
+		{
+			name:   "valid",
+			userID: db.NewID(),
+			code:   http.StatusOK,
+			expect: "payload",
+		},
+		{
+			name:   "empty",
+			userID: "1",
+			code:   http.StatusOK,
+			expect: "[]",
+		},
+		{
+			name:   "op failed",
+			userID: "25",
+			code:   http.StatusInternalServerError,
+			expect: "error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			session := scs.New()
+			h := app.HandleListTags(zap.NewNop(), testdata.NewTG())
+			m := lsm(t, session, tt.userID)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+			require.Equal(t, tt.code, rr.Code)
+
+			rs := rr.Result()
+			defer rs.Body.Close()
+
+			body := readTestBody(t, rs.Body)
+
+			require.Contains(t, body, tt.expect)
+		})
+	}
+}
+
+func TestHandleGetTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		code int
+	}{
+		{
+			name: "valid",
+			tid:  db.NewID(),
+			code: http.StatusOK,
+		},
+		{
+			name: "missing tag",
+			tid:  "1",
+			code: http.StatusNotFound,
+		},
+		{
+			name: "op failed",
+			tid:  "25",
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(setTagID(t, tt.tid))
+
+			session := scs.New()
+			h := app.HandleGetTag(zap.NewNop(), testdata.NewTG())
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleUpdateTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		body string
+		code int
+	}{
+		{
+			name: "valid",
+			tid:  db.NewID(),
+			body: `{"name": "home"}`,
+			code: http.StatusOK,
+		},
+		{
+			name: "bad body",
+			tid:  db.NewID(),
+			body: `{"title": "home"}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "invalid data",
+			tid:  db.NewID(),
+			body: `{"name": ""}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "missing tag",
+			tid:  "1",
+			body: `{"name": "home"}`,
+			code: http.StatusNotFound,
+		},
+		{
+			name: "get failed",
+			tid:  "25",
+			body: `{"name": "home"}`,
+			code: http.StatusInternalServerError,
+		},
+		{
+			name: "duplicate data",
+			tid:  db.NewID(),
+			body: `{"name": "test"}`,
+			code: http.StatusConflict,
+		},
+		{
+			name: "update failed",
+			tid:  db.NewID(),
+			body: `{"name": "testX"}`,
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/", bytes.NewBuffer([]byte(tt.body)))
+			r = r.WithContext(setTagID(t, tt.tid))
+
+			session := scs.New()
+			h := app.HandleUpdateTag(zap.NewNop(), testdata.NewTG())
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleDeleteTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		code int
+	}{
+		{
+			name: "valid",
+			tid:  db.NewID(),
+			code: http.StatusOK,
+		},
+		{
+			name: "missing tag",
+			tid:  "1",
+			code: http.StatusNotFound,
+		},
+		{
+			name: "get failed",
+			tid:  "25",
+			code: http.StatusInternalServerError,
+		},
+		{
+			name: "delete failed",
+			tid:  "201",
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			r = r.WithContext(setTagID(t, tt.tid))
+
+			session := scs.New()
+			h := app.HandleDeleteTag(zap.NewNop(), testdata.NewTG())
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
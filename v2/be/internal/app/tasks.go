@@ -2,8 +2,11 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"v2/be/internal/db"
 	"v2/be/internal/models"
@@ -13,7 +16,24 @@ import (
 	"go.uber.org/zap"
 )
 
-var ErrTaskNotModifed = errors.New("task not modified")
+func validateTaskDates(v *validator.Validator, dueAt, remindAt *time.Time) {
+	v.Check(remindAt == nil || dueAt == nil || !remindAt.After(*dueAt), "remind_at", "must not be after due_at")
+}
+
+// cleanRecurrence normalises an optional RRULE, recording an invalid one in v. An empty rule means no recurrence.
+func cleanRecurrence(rule *string, v *validator.Validator) *string {
+	if rule == nil || strings.TrimSpace(*rule) == "" {
+		return nil
+	}
+
+	clean, err := models.ParseRecurrence(*rule)
+	if err != nil {
+		v.AddError("recurrence", err.Error())
+		return nil
+	}
+
+	return &clean
+}
 
 type TaskCreater interface {
 	Create(ctx context.Context, t *models.Task) error
@@ -24,8 +44,15 @@ func HandleCreateTask(logger *zap.Logger, tc TaskCreater) http.HandlerFunc {
 		id := GetUserID(r)
 
 		var input struct {
-			Title       string `json:"title"`
-			Description string `json:"description"`
+			Title       string     `json:"title"`
+			Description string     `json:"description"`
+			DueAt       *time.Time `json:"due_at"`
+			RemindAt    *time.Time `json:"remind_at"`
+			Recurrence  *string    `json:"recurrence"`
+			Priority    string     `json:"priority"`
+			Tags        []string   `json:"tags"`
+			ProjectID   string     `json:"project_id"`
+			ParentID    *string    `json:"parent_id"`
 		}
 
 		err := parser.Read(w, r, &input)
@@ -40,6 +67,13 @@ func HandleCreateTask(logger *zap.Logger, tc TaskCreater) http.HandlerFunc {
 		v := validator.New()
 		v.RequiredString(input.Title, "title", validator.Required)
 		v.RequiredString(input.Description, "description", validator.Required)
+		validateTaskDates(v, input.DueAt, input.RemindAt)
+		input.Recurrence = cleanRecurrence(input.Recurrence, v)
+		v.Check(input.Priority == "" || validator.PermittedValue(input.Priority, models.TaskPriorities...), "priority", "invalid priority value")
+		input.Tags = cleanTags(input.Tags, v)
+		if input.ParentID != nil {
+			v.RequiredString(*input.ParentID, "parent_id", validator.Required)
+		}
 		if !v.Valid() {
 			InvalidDataError(w, v.Errors())
 			return
@@ -51,6 +85,18 @@ func HandleCreateTask(logger *zap.Logger, tc TaskCreater) http.HandlerFunc {
 			Title:       input.Title,
 			Description: input.Description,
 			Completed:   false,
+			DueAt:       input.DueAt,
+			RemindAt:    input.RemindAt,
+			Recurrence:  input.Recurrence,
+			Priority:    input.Priority,
+			Tags:        input.Tags,
+			ProjectID:   strings.TrimSpace(input.ProjectID),
+			ParentID:    input.ParentID,
+		}
+
+		ws := GetWorkspaceID(r)
+		if ws != "" {
+			t.WorkspaceID = &ws
 		}
 
 		err = tc.Create(r.Context(), t)
@@ -58,6 +104,10 @@ func HandleCreateTask(logger *zap.Logger, tc TaskCreater) http.HandlerFunc {
 			switch {
 			case errors.Is(err, models.ErrDuplicateTask):
 				DuplicateDataError(w, logger, err)
+			case errors.Is(err, models.ErrProjectNotFound), errors.Is(err, models.ErrParentNotFound):
+				MissingDataError(w, logger, err)
+			case errors.Is(err, models.ErrTaskTooDeep):
+				InvalidDataError(w, map[string]string{"parent_id": err.Error()})
 			default:
 				ServerError(w, logger, err)
 			}
@@ -72,14 +122,56 @@ func HandleCreateTask(logger *zap.Logger, tc TaskCreater) http.HandlerFunc {
 }
 
 type TaskLister interface {
-	All(ctx context.Context, userID string) ([]*models.Task, error)
+	All(ctx context.Context, userID string, f models.TaskFilter) ([]*models.Task, string, error)
+}
+
+// readTaskFilter parses the list query parameters of r, recording invalid ones in v
+func readTaskFilter(r *http.Request, v *validator.Validator) models.TaskFilter {
+	qs := r.URL.Query()
+
+	f := models.TaskFilter{
+		WorkspaceID: GetWorkspaceID(r),
+		Completed:   readBool(qs, "completed", v),
+		Query:       readString(qs, "q", ""),
+		Tag:         parser.Sanitize(qs.Get("tag")),
+		Sort:        readString(qs, "sort", "position"),
+		Limit:       readInt(qs, "limit", models.DefaultTaskLimit, v),
+	}
+
+	v.Check(validator.PermittedValue(f.Sort, models.TaskSorts...), "sort", "invalid sort value")
+	v.Check(f.Limit > 0 && f.Limit <= models.MaxTaskLimit, "limit", "must be between 1 and 100")
+
+	assignee := readString(qs, "assignee", "")
+	if assignee != "" {
+		v.Check(assignee == "me", "assignee", "must be me")
+		f.Assigned = true
+	}
+
+	cursor := readString(qs, "cursor", "")
+	if cursor != "" {
+		c, err := models.DecodeCursor(cursor, f.Sort)
+		if err != nil {
+			v.AddError("cursor", err.Error())
+		}
+
+		f.Cursor = c
+	}
+
+	return f
 }
 
 func HandleListTasks(logger *zap.Logger, tl TaskLister) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := GetUserID(r)
 
-		tasks, err := tl.All(r.Context(), id)
+		v := validator.New()
+		f := readTaskFilter(r, v)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		tasks, next, err := tl.All(r.Context(), id, f)
 		if err != nil {
 			ServerError(w, logger, err)
 			return
@@ -89,7 +181,45 @@ func HandleListTasks(logger *zap.Logger, tl TaskLister) http.HandlerFunc {
 			tasks = []*models.Task{}
 		}
 
-		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": tasks})
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": tasks, "next_cursor": next})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TaskSearcher interface {
+	Search(ctx context.Context, userID, q string, limit int) ([]*models.TaskMatch, error)
+}
+
+func HandleSearchTasks(logger *zap.Logger, ts TaskSearcher) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+		qs := r.URL.Query()
+
+		v := validator.New()
+
+		q := parser.Sanitize(qs.Get("q"))
+		limit := readInt(qs, "limit", models.DefaultTaskLimit, v)
+
+		v.RequiredString(q, "q", validator.Required)
+		v.Check(limit > 0 && limit <= models.MaxTaskLimit, "limit", "must be between 1 and 100")
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		matches, err := ts.Search(r.Context(), id, q, limit)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		if matches == nil {
+			matches = []*models.TaskMatch{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": matches})
 		if err != nil {
 			writeError(w)
 		}
@@ -100,14 +230,17 @@ type TaskGetter interface {
 	GetByID(ctx context.Context, id, userID string) (*models.Task, error)
 }
 
-func HandleGetTask(logger *zap.Logger, tg TaskGetter) http.HandlerFunc {
+type TaskDetailer interface {
+	TaskGetter
+	Children(ctx context.Context, id, userID string) ([]*models.Task, error)
+}
+
+func HandleGetTask(logger *zap.Logger, td TaskDetailer) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := GetTaskID(r)
-		userID := GetUserID(r)
-
-		logger.Info(id)
+		userID := GetOwnerID(r)
 
-		t, err := tg.GetByID(r.Context(), id, userID)
+		t, err := td.GetByID(r.Context(), id, userID)
 		if err != nil {
 			switch {
 			case errors.Is(err, models.ErrRecordNotFound):
@@ -118,6 +251,26 @@ func HandleGetTask(logger *zap.Logger, tg TaskGetter) http.HandlerFunc {
 			return
 		}
 
+		t.Subtasks, err = td.Children(r.Context(), t.ID, userID)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		body, err := json.Marshal(t)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		etag := taskETag(t.Version, body)
+		w.Header().Set("ETag", etag)
+
+		if matchETag(r.Header.Get("If-None-Match"), etag) {
+			UnmodifiedDataError(w)
+			return
+		}
+
 		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t})
 		if err != nil {
 			writeError(w)
@@ -130,28 +283,213 @@ type TaskUpdater interface {
 	Update(ctx context.Context, t *models.Task) error
 }
 
+// readTaskUpdate reads a full update of a task, in which title and description
+// are required. Leaving out due_at, remind_at or recurrence clears them, while
+// priority, project_id and tags left out (or tags sent as null) keep their
+// value. It returns the function applying the update to the task.
+func readTaskUpdate(w http.ResponseWriter, r *http.Request, v *validator.Validator) (func(t *models.Task), error) {
+	var input struct {
+		Title       string     `json:"title"`
+		Description string     `json:"description"`
+		DueAt       *time.Time `json:"due_at"`
+		RemindAt    *time.Time `json:"remind_at"`
+		Recurrence  *string    `json:"recurrence"`
+		Priority    string     `json:"priority"`
+		Tags        []string   `json:"tags"`
+		ProjectID   string     `json:"project_id"`
+	}
+
+	err := parser.Read(w, r, &input)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Title = parser.Sanitize(input.Title)
+	input.Description = parser.Sanitize(input.Description)
+
+	v.RequiredString(input.Title, "title", validator.Required)
+	v.RequiredString(input.Description, "description", validator.Required)
+	validateTaskDates(v, input.DueAt, input.RemindAt)
+	input.Recurrence = cleanRecurrence(input.Recurrence, v)
+	v.Check(input.Priority == "" || validator.PermittedValue(input.Priority, models.TaskPriorities...), "priority", "invalid priority value")
+	input.Tags = cleanTags(input.Tags, v)
+
+	return func(t *models.Task) {
+		t.Title = input.Title
+		t.Description = input.Description
+		t.DueAt = input.DueAt
+		t.RemindAt = input.RemindAt
+		t.Recurrence = input.Recurrence
+
+		if input.Priority != "" {
+			t.Priority = input.Priority
+		}
+		t.Tags = input.Tags
+
+		if pid := strings.TrimSpace(input.ProjectID); pid != "" {
+			t.ProjectID = pid
+		}
+	}, nil
+}
+
+// taskPatch is a JSON Merge Patch of the editable fields of a task
+type taskPatch struct {
+	Title       parser.Field[string]    `json:"title"`
+	Description parser.Field[string]    `json:"description"`
+	DueAt       parser.Field[time.Time] `json:"due_at"`
+	RemindAt    parser.Field[time.Time] `json:"remind_at"`
+	Recurrence  parser.Field[string]    `json:"recurrence"`
+	Priority    parser.Field[string]    `json:"priority"`
+	Tags        parser.Field[[]string]  `json:"tags"`
+	ProjectID   parser.Field[string]    `json:"project_id"`
+}
+
+// changes validates the fields present in p and returns the functions
+// changing them on a task, in which null clears an optional field
+func (p *taskPatch) changes(v *validator.Validator) []func(t *models.Task) {
+	var changes []func(t *models.Task)
+
+	if p.Title.Set {
+		title := parser.Sanitize(p.Title.Value)
+		v.RequiredString(title, "title", validator.Required)
+
+		changes = append(changes, func(t *models.Task) { t.Title = title })
+	}
+
+	if p.Description.Set {
+		description := parser.Sanitize(p.Description.Value)
+		v.RequiredString(description, "description", validator.Required)
+
+		changes = append(changes, func(t *models.Task) { t.Description = description })
+	}
+
+	if p.DueAt.Set {
+		var dueAt *time.Time
+		if !p.DueAt.Null {
+			dueAt = &p.DueAt.Value
+		}
+
+		changes = append(changes, func(t *models.Task) { t.DueAt = dueAt })
+	}
+
+	if p.RemindAt.Set {
+		var remindAt *time.Time
+		if !p.RemindAt.Null {
+			remindAt = &p.RemindAt.Value
+		}
+
+		changes = append(changes, func(t *models.Task) { t.RemindAt = remindAt })
+	}
+
+	if p.Recurrence.Set {
+		recurrence := cleanRecurrence(&p.Recurrence.Value, v)
+
+		changes = append(changes, func(t *models.Task) { t.Recurrence = recurrence })
+	}
+
+	if p.Priority.Set {
+		priority := "none"
+		if !p.Priority.Null {
+			priority = p.Priority.Value
+			v.Check(validator.PermittedValue(priority, models.TaskPriorities...), "priority", "invalid priority value")
+		}
+
+		changes = append(changes, func(t *models.Task) { t.Priority = priority })
+	}
+
+	if p.Tags.Set {
+		tags := cleanTags(p.Tags.Value, v)
+		if tags == nil {
+			tags = []string{}
+		}
+
+		changes = append(changes, func(t *models.Task) { t.Tags = tags })
+	}
+
+	if p.ProjectID.Set {
+		pid := strings.TrimSpace(p.ProjectID.Value)
+		v.Check(!p.ProjectID.Null, "project_id", "must not be null")
+		v.RequiredString(pid, "project_id", validator.Required)
+
+		changes = append(changes, func(t *models.Task) { t.ProjectID = pid })
+	}
+
+	return changes
+}
+
+// fields returns the names of the fields present in p
+func (p *taskPatch) fields() []string {
+	present := []struct {
+		name string
+		set  bool
+	}{
+		{"title", p.Title.Set},
+		{"description", p.Description.Set},
+		{"due_at", p.DueAt.Set},
+		{"remind_at", p.RemindAt.Set},
+		{"recurrence", p.Recurrence.Set},
+		{"priority", p.Priority.Set},
+		{"tags", p.Tags.Set},
+		{"project_id", p.ProjectID.Set},
+	}
+
+	var fields []string
+
+	for _, f := range present {
+		if f.set {
+			fields = append(fields, f.name)
+		}
+	}
+
+	return fields
+}
+
+// readTaskPatch reads a JSON Merge Patch of a task. Only the fields present
+// are validated and changed, and null clears an optional field. It returns
+// a nil function when the patch changes nothing.
+func readTaskPatch(w http.ResponseWriter, r *http.Request, v *validator.Validator) (func(t *models.Task), error) {
+	var input taskPatch
+
+	err := parser.Read(w, r, &input)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := input.changes(v)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	return func(t *models.Task) {
+		// tags left out of the patch are kept as they are
+		t.Tags = nil
+
+		for _, change := range changes {
+			change(t)
+		}
+	}, nil
+}
+
+// HandleUpdateTask changes an open task. A body sent as application/merge-patch+json
+// only changes the fields it holds, any other body replaces every editable field.
 func HandleUpdateTask(logger *zap.Logger, tu TaskUpdater) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := GetTaskID(r)
-		userID := GetUserID(r)
+		userID := GetOwnerID(r)
 
-		var input struct {
-			Title       string `json:"title"`
-			Description string `json:"description"`
+		read := readTaskUpdate
+		if parser.HasContentType(r, parser.MergePatch) {
+			read = readTaskPatch
 		}
 
-		err := parser.Read(w, r, &input)
+		v := validator.New()
+
+		apply, err := read(w, r, v)
 		if err != nil {
 			ReadError(w, logger, err)
 			return
 		}
 
-		input.Title = parser.Sanitize(input.Title)
-		input.Description = parser.Sanitize(input.Description)
-
-		v := validator.New()
-		v.RequiredString(input.Title, "title", validator.Required)
-		v.RequiredString(input.Description, "description", validator.Required)
 		if !v.Valid() {
 			InvalidDataError(w, v.Errors())
 			return
@@ -168,30 +506,50 @@ func HandleUpdateTask(logger *zap.Logger, tu TaskUpdater) http.HandlerFunc {
 			return
 		}
 
+		if !checkIfMatch(w, r, logger, t.Version) {
+			return
+		}
+
 		if t.Completed {
-			UnmodifiedDataError(w, logger, ErrTaskNotModifed)
+			ConflictingStateError(w, logger, models.ErrTaskCompleted)
 			return
 		}
 
-		if t.Title != input.Title {
-			t.Title = input.Title
+		// an empty patch changes nothing
+		if apply == nil {
+			err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t.ID})
+			if err != nil {
+				writeError(w)
+			}
+			return
 		}
 
-		if t.Description != input.Description {
-			t.Description = input.Description
+		apply(t)
+
+		// a patch may change one date against the other one already saved
+		validateTaskDates(v, t.DueAt, t.RemindAt)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
 		}
 
 		err = tu.Update(r.Context(), t)
 		if err != nil {
 			switch {
-			case errors.Is(err, models.ErrRecordNotFound):
+			case errors.Is(err, models.ErrRecordNotFound), errors.Is(err, models.ErrProjectNotFound):
 				MissingDataError(w, logger, err)
+			case errors.Is(err, models.ErrDuplicateTask):
+				DuplicateDataError(w, logger, err)
+			case errors.Is(err, models.ErrEditConflict):
+				PreconditionFailedError(w, logger, err)
 			default:
 				ServerError(w, logger, err)
 			}
 			return
 		}
 
+		// no ETag: the response is not the representation GET tags, so
+		// clients read the task again before their next conditional write
 		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t.ID})
 		if err != nil {
 			writeError(w)
@@ -201,13 +559,22 @@ func HandleUpdateTask(logger *zap.Logger, tu TaskUpdater) http.HandlerFunc {
 
 type TaskCompleter interface {
 	TaskGetter
-	Complete(ctx context.Context, id, userID string) error
+	Complete(ctx context.Context, id, userID string, version int64, cascade bool) error
 }
 
+// HandleCompleteTask completes a task. Open blockers always prevent completion. Open
+// subtasks do too unless the cascade query parameter is true, in which case they are completed as well.
 func HandleCompleteTask(logger *zap.Logger, tc TaskCompleter) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := GetTaskID(r)
-		userID := GetUserID(r)
+		userID := GetOwnerID(r)
+
+		v := validator.New()
+		cascade := readBool(r.URL.Query(), "cascade", v)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
 
 		t, err := tc.GetByID(r.Context(), id, userID)
 		if err != nil {
@@ -220,14 +587,143 @@ func HandleCompleteTask(logger *zap.Logger, tc TaskCompleter) http.HandlerFunc {
 			return
 		}
 
+		if !checkIfMatch(w, r, logger, t.Version) {
+			return
+		}
+
 		if t.Completed {
-			UnmodifiedDataError(w, logger, ErrTaskNotModifed)
+			err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t.ID})
+			if err != nil {
+				writeError(w)
+			}
 			return
 		}
 
-		err = tc.Complete(r.Context(), id, userID)
+		err = tc.Complete(r.Context(), id, userID, t.Version, cascade != nil && *cascade)
 		if err != nil {
-			ServerError(w, logger, err)
+			switch {
+			case errors.Is(err, models.ErrOpenSubtasks), errors.Is(err, models.ErrOpenBlockers):
+				ConflictingStateError(w, logger, err)
+			case errors.Is(err, models.ErrEditConflict):
+				PreconditionFailedError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t.ID})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TaskReopener interface {
+	TaskGetter
+	Reopen(ctx context.Context, id, userID string) error
+}
+
+// HandleReopenTask marks a completed task as open again
+func HandleReopenTask(logger *zap.Logger, tr TaskReopener) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		userID := GetOwnerID(r)
+
+		t, err := tr.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		if !t.Completed {
+			err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t.ID})
+			if err != nil {
+				writeError(w)
+			}
+			return
+		}
+
+		err = tr.Reopen(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrDuplicateTask):
+				DuplicateDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t.ID})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TaskMover interface {
+	TaskGetter
+	Move(ctx context.Context, id, userID, targetID string, after bool) error
+}
+
+// HandleMoveTask places a task right before or right after another task in the manual order
+func HandleMoveTask(logger *zap.Logger, tm TaskMover) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		userID := GetOwnerID(r)
+
+		var input struct {
+			Before string `json:"before"`
+			After  string `json:"after"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.Before = strings.TrimSpace(input.Before)
+		input.After = strings.TrimSpace(input.After)
+
+		v := validator.New()
+		v.Check((input.Before == "") != (input.After == ""), "before", "exactly one of before or after is required")
+		v.Check(input.Before != id && input.After != id, "before", "a task cannot be moved next to itself")
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		t, err := tm.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		target, after := input.Before, false
+		if input.After != "" {
+			target, after = input.After, true
+		}
+
+		err = tm.Move(r.Context(), t.ID, userID, target, after)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrMoveTargetNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
 			return
 		}
 
@@ -240,13 +736,13 @@ func HandleCompleteTask(logger *zap.Logger, tc TaskCompleter) http.HandlerFunc {
 
 type TaskDeleter interface {
 	TaskGetter
-	Delete(ctx context.Context, id, userID string) error
+	Delete(ctx context.Context, id, userID string, version int64) error
 }
 
 func HandleDeleteTask(logger *zap.Logger, td TaskDeleter) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := GetTaskID(r)
-		userID := GetUserID(r)
+		userID := GetOwnerID(r)
 
 		t, err := td.GetByID(r.Context(), id, userID)
 		if err != nil {
@@ -259,9 +755,18 @@ func HandleDeleteTask(logger *zap.Logger, td TaskDeleter) http.HandlerFunc {
 			return
 		}
 
-		err = td.Delete(r.Context(), t.ID, userID)
+		if !checkIfMatch(w, r, logger, t.Version) {
+			return
+		}
+
+		err = td.Delete(r.Context(), t.ID, userID, t.Version)
 		if err != nil {
-			ServerError(w, logger, err)
+			switch {
+			case errors.Is(err, models.ErrEditConflict):
+				PreconditionFailedError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
 			return
 		}
 
@@ -9,6 +9,7 @@ import (
 	"v2/be/internal/app"
 	"v2/be/internal/app/testdata"
 	"v2/be/internal/db"
+	"v2/be/internal/models"
 
 	"github.com/alexedwards/scs/v2"
 	"github.com/go-chi/chi/v5"
@@ -26,7 +27,51 @@ func setTaskID(t *testing.T, id string) context.Context {
 	return ctx
 }
 
+// setIfMatch sends tag as the If-Match header of r. An empty tag sends the
+// ETag of the tasks returned by the mock and "-" sends no header.
+func setIfMatch(r *http.Request, tag string) {
+	switch tag {
+	case "":
+		r.Header.Set("If-Match", `"3"`)
+	case "-":
+	default:
+		r.Header.Set("If-Match", tag)
+	}
+}
+
 func TestHandleCreateTask(t *testing.T) {
+	t.Run("with dates", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer([]byte(`{"title": "report", "description": "weekly", "due_at": "2024-08-02T17:00:00Z", "remind_at": "2024-08-02T09:00:00Z"}`)))
+
+		session := scs.New()
+
+		h := app.HandleCreateTask(zap.NewNop(), testdata.NewTM())
+		m := lsm(t, session, db.NewID())
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+		require.Equal(t, http.StatusCreated, rr.Code)
+	})
+
+	t.Run("recurring", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer([]byte(`{"title": "report", "description": "weekly", "due_at": "2024-08-05T09:00:00Z", "recurrence": "FREQ=WEEKLY;BYDAY=MO"}`)))
+
+		session := scs.New()
+
+		h := app.HandleCreateTask(zap.NewNop(), testdata.NewTM())
+		m := lsm(t, session, db.NewID())
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+		require.Equal(t, http.StatusCreated, rr.Code)
+	})
+
 	t.Run("valid", func(t *testing.T) {
 		t.Parallel()
 
@@ -67,6 +112,36 @@ func TestHandleCreateTask(t *testing.T) {
 				body: `{"title": "", "description": "just empty"}`,
 				code: http.StatusUnprocessableEntity,
 			},
+			{
+				name: "reminder after due date",
+				body: `{"title": "running", "description": "late", "due_at": "2024-08-01T09:00:00Z", "remind_at": "2024-08-02T09:00:00Z"}`,
+				code: http.StatusUnprocessableEntity,
+			},
+			{
+				name: "empty tag",
+				body: `{"title": "running", "description": "tagged", "tags": ["fitness", " "]}`,
+				code: http.StatusUnprocessableEntity,
+			},
+			{
+				name: "bad due date",
+				body: `{"title": "running", "description": "late", "due_at": "tomorrow"}`,
+				code: http.StatusBadRequest,
+			},
+			{
+				name: "invalid recurrence",
+				body: `{"title": "running", "description": "again", "recurrence": "FREQ=FORTNIGHTLY"}`,
+				code: http.StatusUnprocessableEntity,
+			},
+			{
+				name: "invalid priority",
+				body: `{"title": "running", "description": "again", "priority": "critical"}`,
+				code: http.StatusUnprocessableEntity,
+			},
+			{
+				name: "recurrence too frequent",
+				body: `{"title": "running", "description": "again", "recurrence": "FREQ=HOURLY"}`,
+				code: http.StatusUnprocessableEntity,
+			},
 			{
 				name: "duplicate data",
 				body: `{"title": "test", "description": "duplicated"}`,
@@ -77,6 +152,26 @@ func TestHandleCreateTask(t *testing.T) {
 				body: `{"title": "testX", "description": "duplicated"}`,
 				code: http.StatusInternalServerError,
 			},
+			{
+				name: "unknown project",
+				body: `{"title": "running", "description": "elsewhere", "project_id": "1"}`,
+				code: http.StatusNotFound,
+			},
+			{
+				name: "unknown parent",
+				body: `{"title": "running", "description": "step", "parent_id": "1"}`,
+				code: http.StatusNotFound,
+			},
+			{
+				name: "parent too deep",
+				body: `{"title": "running", "description": "step", "parent_id": "2"}`,
+				code: http.StatusUnprocessableEntity,
+			},
+			{
+				name: "empty parent",
+				body: `{"title": "running", "description": "step", "parent_id": ""}`,
+				code: http.StatusUnprocessableEntity,
+			},
 		}
 
 		for _, tt := range tests {
@@ -112,7 +207,7 @@ func TestHandleListTasks(t *testing.T) {
 		t.Parallel()
 
 		rr := httptest.NewRecorder()
-		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r := httptest.NewRequest(http.MethodGet, "/?completed=true&q=book&sort=-priority&limit=10&assignee=me", nil)
 
 		session := scs.New()
 		h := app.HandleListTasks(zap.NewNop(), testdata.NewTM())
@@ -128,9 +223,74 @@ func TestHandleListTasks(t *testing.T) {
 		body := readTestBody(t, rs.Body)
 
 		require.Contains(t, body, "payload")
+		require.Contains(t, body, "next_cursor")
 		require.Equal(t, "application/json", rs.Header.Get("Content-Type"))
 	})
 
+	t.Run("invalid params", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			query string
+		}{
+			{
+				name:  "completed",
+				query: "?completed=maybe",
+			},
+			{
+				name:  "sort",
+				query: "?sort=size",
+			},
+			{
+				name:  "limit not int",
+				query: "?limit=ten",
+			},
+			{
+				name:  "limit too large",
+				query: "?limit=1000",
+			},
+			{
+				name:  "cursor",
+				query: "?cursor=nope",
+			},
+			{
+				name:  "cursor of another sort",
+				query: "?sort=priority&cursor=" + (&models.Cursor{Sort: "title", Key: "read", ID: db.NewID()}).Encode(),
+			},
+			{
+				name:  "cursor with unknown priority",
+				query: "?sort=priority&cursor=" + (&models.Cursor{Sort: "priority", Key: "soon", ID: db.NewID()}).Encode(),
+			},
+			{
+				name:  "assignee",
+				query: "?assignee=you",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				rr := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+
+				session := scs.New()
+				h := app.HandleListTasks(zap.NewNop(), testdata.NewTM())
+				m := lsm(t, session, db.NewID())
+
+				session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+				require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				body := readTestBody(t, rs.Body)
+
+				require.Contains(t, body, "error")
+			})
+		}
+	})
+
 	t.Run("empty", func(t *testing.T) {
 		t.Parallel()
 
@@ -178,6 +338,100 @@ func TestHandleListTasks(t *testing.T) {
 	})
 }
 
+func TestHandleSearchTasks(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			query  string
+			expect string
+		}{
+			{
+				name:   "matches",
+				query:  "?q=report",
+				expect: "snippet",
+			},
+			{
+				name:   "no matches",
+				query:  "?q=none",
+				expect: "[]",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				rr := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+
+				session := scs.New()
+				h := app.HandleSearchTasks(zap.NewNop(), testdata.NewTM())
+				m := lsm(t, session, db.NewID())
+
+				session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+				require.Equal(t, http.StatusOK, rr.Code)
+
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				body := readTestBody(t, rs.Body)
+
+				require.Contains(t, body, tt.expect)
+				require.Equal(t, "application/json", rs.Header.Get("Content-Type"))
+			})
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			query string
+			code  int
+		}{
+			{
+				name:  "missing query",
+				query: "",
+				code:  http.StatusUnprocessableEntity,
+			},
+			{
+				name:  "bad limit",
+				query: "?q=report&limit=0",
+				code:  http.StatusUnprocessableEntity,
+			},
+			{
+				name:  "op failed",
+				query: "?q=testX",
+				code:  http.StatusInternalServerError,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				rr := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+
+				session := scs.New()
+				h := app.HandleSearchTasks(zap.NewNop(), testdata.NewTM())
+				m := lsm(t, session, db.NewID())
+
+				session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+
+				require.Equal(t, tt.code, rr.Code)
+
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				body := readTestBody(t, rs.Body)
+
+				require.Contains(t, body, "error")
+			})
+		}
+	})
+}
+
 func TestHandleGetTask(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
 		t.Parallel()
@@ -202,7 +456,58 @@ func TestHandleGetTask(t *testing.T) {
 		body := readTestBody(t, rs.Body)
 
 		require.Contains(t, body, "payload")
+		require.Contains(t, body, "subtasks")
 		require.Equal(t, "application/json", rs.Header.Get("Content-Type"))
+		require.Regexp(t, `^"3-[0-9a-f]{16}"$`, rs.Header.Get("ETag"))
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		t.Parallel()
+
+		session := scs.New()
+		uid := db.NewID()
+
+		h := app.HandleGetTask(zap.NewNop(), testdata.NewTM())
+		m := lsm(t, session, uid)
+
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r = r.WithContext(setTaskID(t, "304"))
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		etag := rr.Header().Get("ETag")
+
+		rr = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("If-None-Match", `"2", W/`+etag)
+		r = r.WithContext(setTaskID(t, "304"))
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+		require.Equal(t, http.StatusNotModified, rr.Code)
+		require.Empty(t, rr.Body.String())
+		require.Equal(t, etag, rr.Header().Get("ETag"))
+	})
+
+	t.Run("representation changed", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+
+		// the version alone does not cover subtasks, dependencies or overdue
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("If-None-Match", `"3"`)
+		r = r.WithContext(setTaskID(t, "304"))
+
+		session := scs.New()
+
+		h := app.HandleGetTask(zap.NewNop(), testdata.NewTM())
+		m := lsm(t, session, db.NewID())
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Contains(t, rr.Body.String(), "subtasks")
 	})
 
 	t.Run("errors", func(t *testing.T) {
@@ -221,6 +526,11 @@ func TestHandleGetTask(t *testing.T) {
 				tid:  "25",
 				code: http.StatusInternalServerError,
 			},
+			{
+				name: "children failed",
+				tid:  "203",
+				code: http.StatusInternalServerError,
+			},
 		}
 
 		for _, tt := range tests {
@@ -258,6 +568,7 @@ func TestHandleUpdateTask(t *testing.T) {
 
 		rr := httptest.NewRecorder()
 		r := httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer([]byte(`{"title": "read", "description": "complete a chapter"}`)))
+		r.Header.Set("If-Match", `W/"2", "3-0123456789abcdef"`)
 		ctx := setTaskID(t, db.NewID())
 		r = r.WithContext(ctx)
 
@@ -276,14 +587,16 @@ func TestHandleUpdateTask(t *testing.T) {
 		body := readTestBody(t, rs.Body)
 		require.Contains(t, body, "payload")
 		require.Equal(t, "application/json", rs.Header.Get("Content-Type"))
+		require.Empty(t, rs.Header.Get("ETag"))
 	})
 
 	t.Run("errors", func(t *testing.T) {
 		tests := []struct {
-			name string
-			tid  string
-			body string
-			code int
+			name    string
+			tid     string
+			body    string
+			ifMatch string
+			code    int
 		}{
 			{
 				name: "bad body",
@@ -297,6 +610,18 @@ func TestHandleUpdateTask(t *testing.T) {
 				body: `{"title":"", "description":"early morning run"}`,
 				code: http.StatusUnprocessableEntity,
 			},
+			{
+				name: "reminder after due date",
+				tid:  db.NewID(),
+				body: `{"title":"run", "description":"late", "due_at":"2024-08-01T09:00:00Z", "remind_at":"2024-08-01T10:00:00Z"}`,
+				code: http.StatusUnprocessableEntity,
+			},
+			{
+				name: "invalid recurrence",
+				tid:  db.NewID(),
+				body: `{"title":"run", "description":"again", "recurrence":"FREQ=WEEKLY;DTSTART=20240801T090000Z"}`,
+				code: http.StatusUnprocessableEntity,
+			},
 			{
 				name: "missing data",
 				tid:  "1",
@@ -313,7 +638,7 @@ func TestHandleUpdateTask(t *testing.T) {
 				name: "completed task",
 				tid:  "345",
 				body: `{"title":"learn testing", "description":"practice TDD"}`,
-				code: http.StatusNotModified,
+				code: http.StatusConflict,
 			},
 			{
 				name: "task not found",
@@ -321,12 +646,51 @@ func TestHandleUpdateTask(t *testing.T) {
 				body: `{"title":"test", "description":"update fails"}`,
 				code: http.StatusNotFound,
 			},
+			{
+				name: "duplicate title",
+				tid:  db.NewID(),
+				body: `{"title":"duplicate", "description":"update fails"}`,
+				code: http.StatusConflict,
+			},
+			{
+				name: "unknown project",
+				tid:  db.NewID(),
+				body: `{"title":"learn testing", "description":"move fails", "project_id":"1"}`,
+				code: http.StatusNotFound,
+			},
 			{
 				name: "op failed",
 				tid:  db.NewID(),
 				body: `{"title":"testX", "description":"update fails"}`,
 				code: http.StatusInternalServerError,
 			},
+			{
+				name:    "missing precondition",
+				tid:     db.NewID(),
+				body:    `{"title":"learn testing", "description":"practice TDD"}`,
+				ifMatch: "-",
+				code:    http.StatusPreconditionRequired,
+			},
+			{
+				name:    "stale version",
+				tid:     db.NewID(),
+				body:    `{"title":"learn testing", "description":"practice TDD"}`,
+				ifMatch: `"2"`,
+				code:    http.StatusPreconditionFailed,
+			},
+			{
+				name:    "weak tag",
+				tid:     db.NewID(),
+				body:    `{"title":"learn testing", "description":"practice TDD"}`,
+				ifMatch: `W/"3"`,
+				code:    http.StatusPreconditionFailed,
+			},
+			{
+				name: "edit conflict",
+				tid:  db.NewID(),
+				body: `{"title":"stale", "description":"changed meanwhile"}`,
+				code: http.StatusPreconditionFailed,
+			},
 		}
 
 		for _, tt := range tests {
@@ -335,6 +699,7 @@ func TestHandleUpdateTask(t *testing.T) {
 
 				rr := httptest.NewRecorder()
 				r := httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer([]byte(tt.body)))
+				setIfMatch(r, tt.ifMatch)
 				ctx := setTaskID(t, tt.tid)
 				r = r.WithContext(ctx)
 
@@ -359,6 +724,59 @@ func TestHandleUpdateTask(t *testing.T) {
 	})
 }
 
+func TestHandleUpdateTaskMergePatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		tid         string
+		contentType string
+		body        string
+		code        int
+	}{
+		{name: "single field", tid: db.NewID(), body: `{"priority":"high"}`, code: http.StatusOK},
+		{name: "clear dates", tid: db.NewID(), body: `{"due_at":null, "remind_at":null, "recurrence":null}`, code: http.StatusOK},
+		{name: "clear tags", tid: db.NewID(), body: `{"tags":null}`, code: http.StatusOK},
+		{name: "charset", tid: db.NewID(), contentType: "application/merge-patch+json; charset=utf-8", body: `{"description":"only this"}`, code: http.StatusOK},
+		{name: "full update without title", tid: db.NewID(), contentType: "application/json", body: `{"priority":"high"}`, code: http.StatusUnprocessableEntity},
+		{name: "empty patch", tid: db.NewID(), body: `{}`, code: http.StatusOK},
+		{name: "unknown key", tid: db.NewID(), body: `{"name":"happier"}`, code: http.StatusBadRequest},
+		{name: "wrong type", tid: db.NewID(), body: `{"title":7}`, code: http.StatusBadRequest},
+		{name: "null title", tid: db.NewID(), body: `{"title":null}`, code: http.StatusUnprocessableEntity},
+		{name: "empty description", tid: db.NewID(), body: `{"description":""}`, code: http.StatusUnprocessableEntity},
+		{name: "null project", tid: db.NewID(), body: `{"project_id":null}`, code: http.StatusUnprocessableEntity},
+		{name: "invalid priority", tid: db.NewID(), body: `{"priority":"later"}`, code: http.StatusUnprocessableEntity},
+		{name: "reminder after due date", tid: db.NewID(), body: `{"due_at":"2024-08-01T09:00:00Z", "remind_at":"2024-08-01T10:00:00Z"}`, code: http.StatusUnprocessableEntity},
+		{name: "missing task", tid: "1", body: `{"priority":"high"}`, code: http.StatusNotFound},
+		{name: "completed task", tid: "345", body: `{"priority":"high"}`, code: http.StatusConflict},
+		{name: "duplicate title", tid: db.NewID(), body: `{"title":"duplicate"}`, code: http.StatusConflict},
+		{name: "unknown project", tid: db.NewID(), body: `{"project_id":"1"}`, code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/", bytes.NewBufferString(tt.body))
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			contentType := tt.contentType
+			if contentType == "" {
+				contentType = "application/merge-patch+json"
+			}
+			r.Header.Set("Content-Type", contentType)
+			r.Header.Set("If-Match", `"3"`)
+
+			h := app.HandleUpdateTask(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
 func TestHandleCompleteTask(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
 		t.Parallel()
@@ -366,6 +784,7 @@ func TestHandleCompleteTask(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		r := httptest.NewRequest(http.MethodPatch, "/", nil)
+		r.Header.Set("If-Match", `"3"`)
 		ctx := setTaskID(t, db.NewID())
 		r = r.WithContext(ctx)
 
@@ -386,11 +805,31 @@ func TestHandleCompleteTask(t *testing.T) {
 		require.Equal(t, "application/json", rs.Header.Get("Content-Type"))
 	})
 
+	t.Run("cascade", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+
+		r := httptest.NewRequest(http.MethodPatch, "/?cascade=true", nil)
+		r.Header.Set("If-Match", `"3"`)
+		r = r.WithContext(setTaskID(t, "202"))
+
+		h := app.HandleCompleteTask(zap.NewNop(), testdata.NewTM())
+
+		session := scs.New()
+		m := lsm(t, session, db.NewID())
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
 	t.Run("errors", func(t *testing.T) {
 		tests := []struct {
-			name string
-			tid  string
-			code int
+			name    string
+			tid     string
+			query   string
+			ifMatch string
+			code    int
 		}{
 			{
 				name: "missing task",
@@ -403,15 +842,48 @@ func TestHandleCompleteTask(t *testing.T) {
 				code: http.StatusInternalServerError,
 			},
 			{
-				name: "completed task",
+				name: "already completed",
 				tid:  "345",
-				code: http.StatusNotModified,
+				code: http.StatusOK,
 			},
 			{
 				name: "completion error",
 				tid:  "200",
 				code: http.StatusInternalServerError,
 			},
+			{
+				name: "open subtasks",
+				tid:  "202",
+				code: http.StatusConflict,
+			},
+			{
+				name: "open blockers",
+				tid:  "204",
+				code: http.StatusConflict,
+			},
+			{
+				name:  "bad cascade",
+				tid:   db.NewID(),
+				query: "?cascade=sometimes",
+				code:  http.StatusUnprocessableEntity,
+			},
+			{
+				name:    "missing precondition",
+				tid:     db.NewID(),
+				ifMatch: "-",
+				code:    http.StatusPreconditionRequired,
+			},
+			{
+				name:    "stale version",
+				tid:     db.NewID(),
+				ifMatch: `"2"`,
+				code:    http.StatusPreconditionFailed,
+			},
+			{
+				name: "edit conflict",
+				tid:  "412",
+				code: http.StatusPreconditionFailed,
+			},
 		}
 
 		for _, tt := range tests {
@@ -420,7 +892,8 @@ func TestHandleCompleteTask(t *testing.T) {
 
 				rr := httptest.NewRecorder()
 
-				r := httptest.NewRequest(http.MethodPatch, "/", nil)
+				r := httptest.NewRequest(http.MethodPatch, "/"+tt.query, nil)
+				setIfMatch(r, tt.ifMatch)
 				ctx := setTaskID(t, tt.tid)
 				r = r.WithContext(ctx)
 
@@ -436,6 +909,168 @@ func TestHandleCompleteTask(t *testing.T) {
 	})
 }
 
+func TestHandleReopenTask(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+
+		r := httptest.NewRequest(http.MethodPatch, "/", nil)
+		r = r.WithContext(setTaskID(t, "345"))
+
+		h := app.HandleReopenTask(zap.NewNop(), testdata.NewTM())
+
+		session := scs.New()
+		m := lsm(t, session, db.NewID())
+
+		session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		rs := rr.Result()
+		defer rs.Body.Close()
+
+		body := readTestBody(t, rs.Body)
+
+		require.Contains(t, body, "payload")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			tid  string
+			uid  string
+			code int
+		}{
+			{
+				name: "missing task",
+				tid:  "1",
+				uid:  db.NewID(),
+				code: http.StatusNotFound,
+			},
+			{
+				name: "already open",
+				tid:  db.NewID(),
+				uid:  db.NewID(),
+				code: http.StatusOK,
+			},
+			{
+				name: "duplicate open task",
+				tid:  "345",
+				uid:  "409",
+				code: http.StatusConflict,
+			},
+			{
+				name: "reopen error",
+				tid:  "345",
+				uid:  "200",
+				code: http.StatusInternalServerError,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				rr := httptest.NewRecorder()
+
+				r := httptest.NewRequest(http.MethodPatch, "/", nil)
+				r = r.WithContext(setTaskID(t, tt.tid))
+
+				h := app.HandleReopenTask(zap.NewNop(), testdata.NewTM())
+
+				session := scs.New()
+				m := lsm(t, session, tt.uid)
+
+				session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+				require.Equal(t, tt.code, rr.Code)
+			})
+		}
+	})
+}
+
+func TestHandleMoveTask(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		body string
+		code int
+	}{
+		{
+			name: "before",
+			tid:  db.NewID(),
+			body: `{"before": "` + db.NewID() + `"}`,
+			code: http.StatusOK,
+		},
+		{
+			name: "after",
+			tid:  db.NewID(),
+			body: `{"after": "` + db.NewID() + `"}`,
+			code: http.StatusOK,
+		},
+		{
+			name: "bad body",
+			tid:  db.NewID(),
+			body: `{"below": "2"}`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "no target",
+			tid:  db.NewID(),
+			body: `{}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "both targets",
+			tid:  db.NewID(),
+			body: `{"before": "2", "after": "3"}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "itself",
+			tid:  "7",
+			body: `{"after": "7"}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "missing task",
+			tid:  "1",
+			body: `{"after": "7"}`,
+			code: http.StatusNotFound,
+		},
+		{
+			name: "missing target",
+			tid:  db.NewID(),
+			body: `{"after": "1"}`,
+			code: http.StatusNotFound,
+		},
+		{
+			name: "move error",
+			tid:  db.NewID(),
+			body: `{"before": "25"}`,
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPatch, "/", bytes.NewBufferString(tt.body))
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			h := app.HandleMoveTask(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
 func TestHandleDeleteTask(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
 		t.Parallel()
@@ -443,6 +1078,7 @@ func TestHandleDeleteTask(t *testing.T) {
 		rr := httptest.NewRecorder()
 
 		r := httptest.NewRequest(http.MethodDelete, "/", nil)
+		r.Header.Set("If-Match", `"3"`)
 		ctx := setTaskID(t, db.NewID())
 		r = r.WithContext(ctx)
 
@@ -466,9 +1102,10 @@ func TestHandleDeleteTask(t *testing.T) {
 
 	t.Run("errors", func(t *testing.T) {
 		tests := []struct {
-			name string
-			tid  string
-			code int
+			name    string
+			tid     string
+			ifMatch string
+			code    int
 		}{
 			{
 				name: "missing task",
@@ -485,6 +1122,23 @@ func TestHandleDeleteTask(t *testing.T) {
 				tid:  "201",
 				code: http.StatusInternalServerError,
 			},
+			{
+				name:    "missing precondition",
+				tid:     db.NewID(),
+				ifMatch: "-",
+				code:    http.StatusPreconditionRequired,
+			},
+			{
+				name:    "stale version",
+				tid:     db.NewID(),
+				ifMatch: `"2"`,
+				code:    http.StatusPreconditionFailed,
+			},
+			{
+				name: "edit conflict",
+				tid:  "412",
+				code: http.StatusPreconditionFailed,
+			},
 		}
 
 		for _, tt := range tests {
@@ -494,6 +1148,7 @@ func TestHandleDeleteTask(t *testing.T) {
 				rr := httptest.NewRecorder()
 
 				r := httptest.NewRequest(http.MethodDelete, "/", nil)
+				setIfMatch(r, tt.ifMatch)
 				ctx := setTaskID(t, tt.tid)
 				r = r.WithContext(ctx)
 
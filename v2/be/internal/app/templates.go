@@ -0,0 +1,222 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+	"v2/be/internal/validator"
+
+	"go.uber.org/zap"
+)
+
+type TemplateCreater interface {
+	Create(ctx context.Context, t *models.Template) error
+}
+
+func HandleCreateTemplate(logger *zap.Logger, tc TemplateCreater) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		var input struct {
+			Name        string                   `json:"name"`
+			Title       string                   `json:"title"`
+			Description string                   `json:"description"`
+			Subtasks    []models.TemplateSubtask `json:"subtasks"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.Name = parser.Sanitize(input.Name)
+		input.Title = parser.Sanitize(input.Title)
+		input.Description = parser.Sanitize(input.Description)
+
+		v := validator.New()
+		v.RequiredString(input.Name, "name", validator.Required)
+		v.RequiredString(input.Title, "title", validator.Required)
+		v.RequiredString(input.Description, "description", validator.Required)
+		v.Check(len(input.Subtasks) <= models.MaxTemplateSubtasks, "subtasks", "must not hold more than 50 subtasks")
+		for i := range input.Subtasks {
+			s := &input.Subtasks[i]
+			s.Title = parser.Sanitize(s.Title)
+			s.Description = parser.Sanitize(s.Description)
+			v.Check(s.Title != "" && s.Description != "", "subtasks", "every subtask needs a title and a description")
+		}
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		if input.Subtasks == nil {
+			input.Subtasks = []models.TemplateSubtask{}
+		}
+
+		t := &models.Template{
+			ID:          db.NewID(),
+			UserID:      id,
+			Name:        input.Name,
+			Title:       input.Title,
+			Description: input.Description,
+			Subtasks:    input.Subtasks,
+		}
+
+		err = tc.Create(r.Context(), t)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrDuplicateTemplate):
+				DuplicateDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusCreated, parser.Envelope{"payload": t})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TemplateLister interface {
+	All(ctx context.Context, userID string) ([]*models.Template, error)
+}
+
+func HandleListTemplates(logger *zap.Logger, tl TemplateLister) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		templates, err := tl.All(r.Context(), id)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		if templates == nil {
+			templates = []*models.Template{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": templates})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TemplateGetter interface {
+	GetByID(ctx context.Context, id, userID string) (*models.Template, error)
+}
+
+func HandleGetTemplate(logger *zap.Logger, tg TemplateGetter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTemplateID(r)
+		userID := GetUserID(r)
+
+		t, err := tg.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TemplateDeleter interface {
+	Delete(ctx context.Context, id, userID string) error
+}
+
+func HandleDeleteTemplate(logger *zap.Logger, td TemplateDeleter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTemplateID(r)
+		userID := GetUserID(r)
+
+		err := td.Delete(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": "deleted"})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TemplateInstantiater interface {
+	Instantiate(ctx context.Context, id, userID, projectID string, vars map[string]string) (string, error)
+}
+
+// HandleInstantiateTemplate creates a task and its subtasks from a template,
+// filling in its placeholders with the given variables
+func HandleInstantiateTemplate(logger *zap.Logger, ti TemplateInstantiater) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTemplateID(r)
+		userID := GetUserID(r)
+
+		var input struct {
+			ProjectID string            `json:"project_id"`
+			Variables map[string]string `json:"variables"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		v := validator.New()
+		for name, value := range input.Variables {
+			value = parser.Sanitize(value)
+			v.Check(value != "", "variables", "values must not be empty")
+
+			input.Variables[name] = value
+		}
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		taskID, err := ti.Instantiate(r.Context(), id, userID, strings.TrimSpace(input.ProjectID), input.Variables)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound), errors.Is(err, models.ErrProjectNotFound):
+				MissingDataError(w, logger, err)
+			case errors.Is(err, models.ErrMissingVariable):
+				InvalidDataError(w, map[string]string{"variables": err.Error()})
+			case errors.Is(err, models.ErrDuplicateTask):
+				DuplicateDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusCreated, parser.Envelope{"payload": taskID})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setTemplateID(t *testing.T, id string) context.Context {
+	t.Helper()
+
+	rtx := chi.NewRouteContext()
+	rtx.URLParams.Add("template_id", id)
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rtx)
+
+	return ctx
+}
+
+func TestHandleCreateTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{
+			name: "valid",
+			body: `{"name": "release", "title": "Release {{version}}", "description": "ship it", "subtasks": [{"title": "Tag {{version}}", "description": "git tag"}]}`,
+			code: http.StatusCreated,
+		},
+		{name: "no subtasks", body: `{"name": "review", "title": "Review {{date}}", "description": "weekly"}`, code: http.StatusCreated},
+		{name: "bad body", body: `{"name": "release", "steps": []}`, code: http.StatusBadRequest},
+		{name: "empty title", body: `{"name": "release", "title": " ", "description": "ship it"}`, code: http.StatusUnprocessableEntity},
+		{
+			name: "empty subtask",
+			body: `{"name": "release", "title": "Release", "description": "ship it", "subtasks": [{"title": "", "description": "git tag"}]}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{name: "duplicate", body: `{"name": "test", "title": "Release", "description": "ship it"}`, code: http.StatusConflict},
+		{name: "create error", body: `{"name": "broken", "title": "Release", "description": "ship it"}`, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.body))
+
+			h := app.HandleCreateTemplate(zap.NewNop(), testdata.NewTPM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusCreated {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), `"subtasks":[`)
+			}
+		})
+	}
+}
+
+func TestHandleListTemplates(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		want string
+		code int
+	}{
+		{name: "valid", uid: db.NewID(), want: `"name":"release"`, code: http.StatusOK},
+		{name: "no templates", uid: "204", want: `"payload":[]`, code: http.StatusOK},
+		{name: "list error", uid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			h := app.HandleListTemplates(zap.NewNop(), testdata.NewTPM())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusOK {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleGetTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		code int
+	}{
+		{name: "valid", id: db.NewID(), code: http.StatusOK},
+		{name: "missing template", id: "1", code: http.StatusNotFound},
+		{name: "get error", id: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(setTemplateID(t, tt.id))
+
+			h := app.HandleGetTemplate(zap.NewNop(), testdata.NewTPM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleDeleteTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		code int
+	}{
+		{name: "valid", id: db.NewID(), code: http.StatusOK},
+		{name: "missing template", id: "1", code: http.StatusNotFound},
+		{name: "delete error", id: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			r = r.WithContext(setTemplateID(t, tt.id))
+
+			h := app.HandleDeleteTemplate(zap.NewNop(), testdata.NewTPM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleInstantiateTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		body string
+		code int
+	}{
+		{name: "valid", id: db.NewID(), body: `{"variables": {"version": "1.2.0"}}`, code: http.StatusCreated},
+		{name: "no variables", id: db.NewID(), body: `{}`, code: http.StatusCreated},
+		{name: "bad body", id: db.NewID(), body: `{"vars": {}}`, code: http.StatusBadRequest},
+		{name: "empty value", id: db.NewID(), body: `{"variables": {"version": " "}}`, code: http.StatusUnprocessableEntity},
+		{name: "missing template", id: "1", body: `{}`, code: http.StatusNotFound},
+		{name: "missing project", id: db.NewID(), body: `{"project_id": "1"}`, code: http.StatusNotFound},
+		{name: "missing variable", id: "422", body: `{}`, code: http.StatusUnprocessableEntity},
+		{name: "duplicate task", id: "409", body: `{}`, code: http.StatusConflict},
+		{name: "instantiate error", id: "25", body: `{}`, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.body))
+			r = r.WithContext(setTemplateID(t, tt.id))
+
+			h := app.HandleInstantiateTemplate(zap.NewNop(), testdata.NewTPM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package testdata
+
+import (
+	"context"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+)
+
+type AC struct{}
+
+func NewAC() *AC {
+	return &AC{}
+}
+
+// access maps the magic ids used by the tests to an access level
+func access(id, userID string) (*models.Access, error) {
+	switch id {
+	case "1":
+		return nil, models.ErrRecordNotFound
+	case "25":
+		return nil, models.ErrOpFailed
+	case "viewer":
+		return &models.Access{OwnerID: db.NewID(), Role: models.RoleViewer}, nil
+	case "assignee":
+		return &models.Access{OwnerID: db.NewID(), Role: models.RoleAssignee}, nil
+	case "workspace":
+		ws := "ws"
+		return &models.Access{OwnerID: db.NewID(), WorkspaceID: &ws, Role: models.RoleEditor}, nil
+	case "editor":
+		return &models.Access{OwnerID: db.NewID(), Role: models.RoleEditor}, nil
+	default:
+		return &models.Access{OwnerID: userID, Role: models.RoleOwner}, nil
+	}
+}
+
+func (m *AC) Task(ctx context.Context, taskID, userID string) (*models.Access, error) {
+	return access(taskID, userID)
+}
+
+func (m *AC) Attachment(ctx context.Context, id, userID string) (*models.Access, error) {
+	return access(id, userID)
+}
@@ -0,0 +1,97 @@
+package testdata
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+)
+
+type AM struct{}
+
+func NewAM() *AM {
+	return &AM{}
+}
+
+func (m *AM) Create(ctx context.Context, a *models.Attachment, r io.Reader) error {
+	if a.TaskID == "1" {
+		return models.ErrRecordNotFound
+	}
+
+	if a.TaskID == "25" {
+		return models.ErrOpFailed
+	}
+
+	if a.TaskID == "413" {
+		return models.ErrQuotaExceeded
+	}
+
+	n, err := io.Copy(io.Discard, r)
+	if err != nil {
+		return err
+	}
+
+	a.Size = n
+	a.CreatedAt = time.Now()
+
+	return nil
+}
+
+func (m *AM) All(ctx context.Context, taskID, userID string) ([]*models.Attachment, error) {
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	if taskID == "204" {
+		return nil, nil
+	}
+
+	a := &models.Attachment{
+		ID:          db.NewID(),
+		TaskID:      taskID,
+		UserID:      userID,
+		Name:        "notes.txt",
+		ContentType: "text/plain; charset=utf-8",
+		Size:        5,
+		CreatedAt:   time.Now(),
+	}
+
+	return []*models.Attachment{a}, nil
+}
+
+func (m *AM) Open(ctx context.Context, id, userID string) (*models.Attachment, io.ReadCloser, error) {
+	if id == "1" {
+		return nil, nil, models.ErrRecordNotFound
+	}
+
+	if id == "25" {
+		return nil, nil, models.ErrOpFailed
+	}
+
+	a := &models.Attachment{
+		ID:          id,
+		TaskID:      db.NewID(),
+		UserID:      userID,
+		Name:        "notes.txt",
+		ContentType: "text/plain; charset=utf-8",
+		Size:        5,
+		CreatedAt:   time.Now(),
+	}
+
+	return a, io.NopCloser(strings.NewReader("hello")), nil
+}
+
+func (m *AM) Delete(ctx context.Context, id, userID string) error {
+	if id == "1" {
+		return models.ErrRecordNotFound
+	}
+
+	if id == "25" {
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
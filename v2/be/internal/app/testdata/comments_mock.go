@@ -0,0 +1,94 @@
+package testdata
+
+import (
+	"context"
+	"time"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+type CM struct{}
+
+func NewCM() *CM {
+	return &CM{}
+}
+
+func (m *CM) Create(ctx context.Context, c *models.Comment) error {
+	if c.TaskID == "1" {
+		return models.ErrRecordNotFound
+	}
+
+	if c.TaskID == "25" {
+		return models.ErrOpFailed
+	}
+
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = c.CreatedAt
+
+	return nil
+}
+
+func (m *CM) All(ctx context.Context, taskID, userID string) ([]*models.Comment, error) {
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	if taskID == "204" {
+		return nil, nil
+	}
+
+	c := &models.Comment{
+		ID:     db.NewID(),
+		TaskID: taskID,
+		UserID: userID,
+		Author: gofakeit.Username(),
+		Body:   gofakeit.Sentence(8),
+	}
+
+	return []*models.Comment{c}, nil
+}
+
+func (m *CM) GetByID(ctx context.Context, id, userID string) (*models.Comment, error) {
+	if id == "1" {
+		return nil, models.ErrRecordNotFound
+	}
+
+	if id == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	c := &models.Comment{
+		ID:     id,
+		TaskID: db.NewID(),
+		UserID: userID,
+		Author: gofakeit.Username(),
+		Body:   gofakeit.Sentence(8),
+	}
+
+	if id == "403" {
+		c.UserID = db.NewID()
+	}
+
+	return c, nil
+}
+
+func (m *CM) Update(ctx context.Context, c *models.Comment) error {
+	if c.ID == "26" {
+		return models.ErrOpFailed
+	}
+
+	c.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (m *CM) Delete(ctx context.Context, id, userID string) error {
+	if id == "26" {
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package testdata
+
+import (
+	"context"
+	"sync"
+
+	"v2/be/internal/models"
+)
+
+type IM struct {
+	mu       sync.Mutex
+	Key      string
+	Saved    *models.StoredResponse
+	Released bool
+}
+
+func NewIM() *IM {
+	return &IM{}
+}
+
+func (m *IM) Reserve(ctx context.Context, userID, key string, fingerprint []byte) (*models.StoredResponse, error) {
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	switch key {
+	case "reused":
+		return nil, models.ErrIdempotencyKeyReused
+	case "busy":
+		return nil, models.ErrIdempotencyKeyInFlight
+	case "stored":
+		return &models.StoredResponse{
+			Status:      201,
+			ContentType: "application/json",
+			Body:        []byte(`{"payload":"stored"}`),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func (m *IM) Save(ctx context.Context, userID, key string, res *models.StoredResponse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Key = key
+	m.Saved = res
+
+	return nil
+}
+
+func (m *IM) Release(ctx context.Context, userID, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Released = true
+
+	return nil
+}
+
+// KeyPurger counts its calls and cancels the purge loop once it has run twice
+type KeyPurger struct {
+	Calls  int
+	Cancel context.CancelFunc
+}
+
+func (p *KeyPurger) Purge(ctx context.Context) (int64, error) {
+	p.Calls++
+	if p.Calls >= 2 {
+		p.Cancel()
+	}
+
+	return 1, nil
+}
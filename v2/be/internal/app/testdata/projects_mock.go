@@ -0,0 +1,99 @@
+package testdata
+
+import (
+	"context"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+type PM struct{}
+
+func NewPM() *PM {
+	return &PM{}
+}
+
+func (m *PM) Create(ctx context.Context, p *models.Project) error {
+	if p.Name == "test" {
+		return models.ErrDuplicateProject
+	}
+
+	if p.Name == "testX" {
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
+
+func (m *PM) CreateInbox(ctx context.Context, userID string) (string, error) {
+	return db.NewID(), nil
+}
+
+func (m *PM) All(ctx context.Context, userID string) ([]*models.Project, error) {
+	if userID == "1" {
+		return nil, nil
+	}
+
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	p := &models.Project{
+		ID:     db.NewID(),
+		UserID: userID,
+		Name:   models.InboxName,
+		Inbox:  true,
+	}
+
+	return []*models.Project{p}, nil
+}
+
+func (m *PM) GetByID(ctx context.Context, id, userID string) (*models.Project, error) {
+	if id == "1" {
+		return nil, models.ErrRecordNotFound
+	}
+
+	if id == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	if id == "345" {
+		p := &models.Project{
+			ID:     id,
+			UserID: userID,
+			Name:   models.InboxName,
+			Inbox:  true,
+		}
+		return p, nil
+	}
+
+	p := &models.Project{
+		ID:     id,
+		UserID: userID,
+		Name:   gofakeit.AppName(),
+	}
+
+	return p, nil
+}
+
+func (m *PM) Update(ctx context.Context, p *models.Project) error {
+	if p.Name == "test" {
+		return models.ErrDuplicateProject
+	}
+
+	if p.Name == "testX" {
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
+
+func (m *PM) Delete(ctx context.Context, id, userID string) error {
+	if id == "201" {
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
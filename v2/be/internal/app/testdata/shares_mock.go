@@ -0,0 +1,75 @@
+package testdata
+
+import (
+	"context"
+	"time"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+type SM struct{}
+
+func NewSM() *SM {
+	return &SM{}
+}
+
+func (m *SM) Create(ctx context.Context, s *models.Share) error {
+	switch s.Username {
+	case "nobody":
+		return models.ErrShareUserNotFound
+	case "me":
+		return models.ErrShareWithSelf
+	case "taken":
+		return models.ErrDuplicateShare
+	case "broken":
+		return models.ErrOpFailed
+	}
+
+	if s.TaskID != nil && *s.TaskID == "1" {
+		return models.ErrRecordNotFound
+	}
+
+	s.UserID = db.NewID()
+	s.CreatedAt = time.Now()
+
+	return nil
+}
+
+func (m *SM) All(ctx context.Context, userID string) ([]*models.Share, error) {
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	if userID == "204" {
+		return nil, nil
+	}
+
+	taskID := db.NewID()
+
+	s := &models.Share{
+		ID:        db.NewID(),
+		OwnerID:   userID,
+		UserID:    db.NewID(),
+		Username:  gofakeit.Username(),
+		TaskID:    &taskID,
+		Role:      models.RoleViewer,
+		CreatedAt: time.Now(),
+	}
+
+	return []*models.Share{s}, nil
+}
+
+func (m *SM) Delete(ctx context.Context, id, userID string) error {
+	if id == "1" {
+		return models.ErrRecordNotFound
+	}
+
+	if id == "25" {
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package testdata
+
+import (
+	"context"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+type TG struct{}
+
+func NewTG() *TG {
+	return &TG{}
+}
+
+func (m *TG) Create(ctx context.Context, t *models.Tag) error {
+	if t.Name == "test" {
+		return models.ErrDuplicateTag
+	}
+
+	if t.Name == "testX" {
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
+
+func (m *TG) All(ctx context.Context, userID string) ([]*models.Tag, error) {
+	if userID == "1" {
+		return nil, nil
+	}
+
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	t := &models.Tag{
+		ID:     db.NewID(),
+		UserID: userID,
+		Name:   gofakeit.Word(),
+	}
+
+	return []*models.Tag{t}, nil
+}
+
+func (m *TG) GetByID(ctx context.Context, id, userID string) (*models.Tag, error) {
+	if id == "1" {
+		return nil, models.ErrRecordNotFound
+	}
+
+	if id == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	t := &models.Tag{
+		ID:     id,
+		UserID: userID,
+		Name:   gofakeit.Word(),
+	}
+
+	return t, nil
+}
+
+func (m *TG) Update(ctx context.Context, t *models.Tag) error {
+	if t.Name == "test" {
+		return models.ErrDuplicateTag
+	}
+
+	if t.Name == "testX" {
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
+
+func (m *TG) Delete(ctx context.Context, id, userID string) error {
+	if id == "201" {
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
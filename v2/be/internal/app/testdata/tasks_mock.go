@@ -2,6 +2,8 @@ package testdata
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"v2/be/internal/db"
 	"v2/be/internal/models"
@@ -20,20 +22,32 @@ func (m *TM) Create(ctx context.Context, t *models.Task) error {
 		return models.ErrDuplicateTask
 	}
 
+	if t.ProjectID == "1" {
+		return models.ErrProjectNotFound
+	}
+
 	if t.Title == "testX" {
 		return models.ErrOpFailed
 	}
 
+	if t.ParentID != nil && *t.ParentID == "1" {
+		return models.ErrParentNotFound
+	}
+
+	if t.ParentID != nil && *t.ParentID == "2" {
+		return models.ErrTaskTooDeep
+	}
+
 	return nil
 }
 
-func (m *TM) All(ctx context.Context, userID string) ([]*models.Task, error) {
+func (m *TM) All(ctx context.Context, userID string, f models.TaskFilter) ([]*models.Task, string, error) {
 	if userID == "1" {
-		return nil, nil
+		return nil, "", nil
 	}
 
 	if userID == "25" {
-		return nil, models.ErrOpFailed
+		return nil, "", models.ErrOpFailed
 	}
 
 	t := &models.Task{
@@ -44,7 +58,28 @@ func (m *TM) All(ctx context.Context, userID string) ([]*models.Task, error) {
 		Completed:   true,
 	}
 
-	return []*models.Task{t}, nil
+	next := (&models.Cursor{Sort: f.Sort, ID: t.ID}).Encode()
+
+	return []*models.Task{t}, next, nil
+}
+
+func (m *TM) Search(ctx context.Context, userID, q string, limit int) ([]*models.TaskMatch, error) {
+	if q == "none" {
+		return nil, nil
+	}
+
+	if q == "testX" {
+		return nil, models.ErrOpFailed
+	}
+
+	t := &models.Task{
+		ID:          db.NewID(),
+		UserID:      userID,
+		Title:       q,
+		Description: gofakeit.Blurb(),
+	}
+
+	return []*models.TaskMatch{{Task: t, Rank: 0.6, Snippet: "<mark>" + q + "</mark>"}}, nil
 }
 
 func (m *TM) GetByID(ctx context.Context, id, userID string) (*models.Task, error) {
@@ -62,7 +97,21 @@ func (m *TM) GetByID(ctx context.Context, id, userID string) (*models.Task, erro
 			UserID:      userID,
 			Title:       gofakeit.BookTitle(),
 			Description: gofakeit.Blurb(),
+			Status:      "done",
 			Completed:   true,
+			Version:     3,
+		}
+		return c, nil
+	}
+
+	if id == "304" {
+		c := &models.Task{
+			ID:          id,
+			UserID:      userID,
+			Title:       "read",
+			Description: "a chapter",
+			Status:      "todo",
+			Version:     3,
 		}
 		return c, nil
 	}
@@ -72,6 +121,8 @@ func (m *TM) GetByID(ctx context.Context, id, userID string) (*models.Task, erro
 		UserID:      userID,
 		Title:       gofakeit.BookTitle(),
 		Description: gofakeit.Blurb(),
+		Status:      "todo",
+		Version:     3,
 	}
 
 	return t, nil
@@ -82,25 +133,345 @@ func (m *TM) Update(ctx context.Context, t *models.Task) error {
 		return models.ErrRecordNotFound
 	}
 
+	if t.Title == "duplicate" {
+		return models.ErrDuplicateTask
+	}
+
+	if t.ProjectID == "1" {
+		return models.ErrProjectNotFound
+	}
+
 	if t.Title == "testX" {
 		return models.ErrOpFailed
 	}
 
+	if t.Title == "stale" {
+		return models.ErrEditConflict
+	}
+
+	t.Version++
+
 	return nil
 }
 
-func (m *TM) Complete(ctx context.Context, id, userID string) error {
+func (m *TM) Children(ctx context.Context, id, userID string) ([]*models.Task, error) {
+	if id == "203" {
+		return nil, models.ErrOpFailed
+	}
+
+	if id == "304" {
+		c := &models.Task{ID: "3041", UserID: userID, ParentID: &id, Title: "skim", Description: "the summary"}
+		return []*models.Task{c}, nil
+	}
+
+	c := &models.Task{
+		ID:          db.NewID(),
+		UserID:      userID,
+		ParentID:    &id,
+		Title:       gofakeit.BookTitle(),
+		Description: gofakeit.Blurb(),
+	}
+
+	return []*models.Task{c}, nil
+}
+
+func (m *TM) Complete(ctx context.Context, id, userID string, version int64, cascade bool) error {
 	if id == "200" {
 		return models.ErrOpFailed
 	}
 
+	if id == "412" {
+		return models.ErrEditConflict
+	}
+
+	if id == "202" && !cascade {
+		return models.ErrOpenSubtasks
+	}
+
+	if id == "204" {
+		return models.ErrOpenBlockers
+	}
+
+	return nil
+}
+
+func (m *TM) Reopen(ctx context.Context, id, userID string) error {
+	if id == "345" && userID == "200" {
+		return models.ErrOpFailed
+	}
+
+	if id == "345" && userID == "409" {
+		return models.ErrDuplicateTask
+	}
+
+	return nil
+}
+
+func (m *TM) Move(ctx context.Context, id, userID, targetID string, after bool) error {
+	if targetID == "1" {
+		return models.ErrMoveTargetNotFound
+	}
+
+	if targetID == "25" {
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
+
+func (m *TM) AddBlocker(ctx context.Context, id, blockerID, userID string) error {
+	switch blockerID {
+	case "1":
+		return models.ErrRecordNotFound
+	case "409":
+		return models.ErrDuplicateDependency
+	case "410":
+		return models.ErrDependencyCycle
+	case "25":
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
+
+func (m *TM) RemoveBlocker(ctx context.Context, id, blockerID, userID string) error {
+	switch blockerID {
+	case "1":
+		return models.ErrRecordNotFound
+	case "25":
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
+
+func (m *TM) SetStatus(ctx context.Context, id, userID, status string) error {
+	switch status {
+	case "unknown":
+		return models.ErrStatusNotFound
+	case "review":
+		return models.ErrInvalidTransition
+	case "blocked":
+		return models.ErrOpenBlockers
+	case "broken":
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
+
+func (m *TM) Assign(ctx context.Context, id, userID string, assigneeID *string) error {
+	if assigneeID == nil {
+		return nil
+	}
+
+	switch *assigneeID {
+	case "1":
+		return models.ErrAssigneeNotFound
+	case "25":
+		return models.ErrOpFailed
+	}
+
 	return nil
 }
 
-func (m *TM) Delete(ctx context.Context, id, userID string) error {
+func (m *TM) Archive(ctx context.Context, id, userID string) error {
+	switch id {
+	case "1":
+		return models.ErrRecordNotFound
+	case "25":
+		return models.ErrOpFailed
+	case "304":
+		return models.ErrTaskArchived
+	case "409":
+		return models.ErrTaskNotCompleted
+	}
+
+	return nil
+}
+
+func (m *TM) ArchiveCompleted(ctx context.Context, userID string) (int64, error) {
+	if userID == "25" {
+		return 0, models.ErrOpFailed
+	}
+
+	return 3, nil
+}
+
+func (m *TM) Bulk(ctx context.Context, userID string, ops []models.BulkOp) ([]*models.BulkResult, error) {
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	results := make([]*models.BulkResult, 0, len(ops))
+	for _, op := range ops {
+		res := &models.BulkResult{TaskID: op.TaskID, Op: op.Op}
+
+		switch op.TaskID {
+		case "1":
+			res.Err = models.ErrRecordNotFound
+		case "25":
+			res.Err = errors.New("connection reset")
+		case "202":
+			res.Err = models.ErrOpFailed
+		case "304":
+			res.Err = models.ErrTaskCompleted
+		case "409":
+			res.Err = models.ErrOpenSubtasks
+		case "412":
+			res.Err = models.ErrEditConflict
+		case "422":
+			res.Err = models.ErrInvalidRecurrence
+		}
+
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+func (m *TM) Delete(ctx context.Context, id, userID string, version int64) error {
 	if id == "201" {
 		return models.ErrOpFailed
 	}
 
+	if id == "412" {
+		return models.ErrEditConflict
+	}
+
 	return nil
 }
+
+func (m *TM) Trash(ctx context.Context, userID string) ([]*models.Task, error) {
+	if userID == "1" {
+		return nil, nil
+	}
+
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	now := time.Now()
+
+	t := &models.Task{
+		ID:          db.NewID(),
+		UserID:      userID,
+		Title:       gofakeit.BookTitle(),
+		Description: gofakeit.Blurb(),
+		DeletedAt:   &now,
+	}
+
+	return []*models.Task{t}, nil
+}
+
+func (m *TM) Restore(ctx context.Context, id, userID string) error {
+	switch id {
+	case "1":
+		return models.ErrRecordNotFound
+	case "409":
+		return models.ErrDuplicateTask
+	case "25":
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
+
+func (m *TM) EmptyTrash(ctx context.Context, userID string) (int64, error) {
+	if userID == "25" {
+		return 0, models.ErrOpFailed
+	}
+
+	return 2, nil
+}
+
+// Purger counts its calls and cancels the purge loop once it has run twice
+type Purger struct {
+	Calls  int
+	Cancel context.CancelFunc
+}
+
+func (p *Purger) Purge(ctx context.Context, before time.Time) (int64, error) {
+	p.Calls++
+	if p.Calls >= 2 {
+		p.Cancel()
+	}
+
+	return 1, nil
+}
+
+func (m *TM) History(ctx context.Context, id, userID string) ([]*models.TaskEvent, error) {
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	if id == "204" {
+		return nil, nil
+	}
+
+	e := &models.TaskEvent{
+		ID:     db.NewID(),
+		TaskID: id,
+		UserID: userID,
+		Action: models.TaskUpdated,
+		Changes: map[string]models.FieldChange{
+			"title": {Old: gofakeit.BookTitle(), New: gofakeit.BookTitle()},
+		},
+		CreatedAt: time.Now(),
+	}
+
+	return []*models.TaskEvent{e}, nil
+}
+
+func (m *TM) Changes(ctx context.Context, userID string, since *models.SyncToken) (*models.SyncChanges, error) {
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	changes := &models.SyncChanges{
+		Tasks: []*models.Task{{
+			ID:          db.NewID(),
+			UserID:      userID,
+			Title:       gofakeit.BookTitle(),
+			Description: gofakeit.Blurb(),
+			Version:     3,
+		}},
+		Deleted: []*models.Tombstone{},
+		Token:   (&models.SyncToken{XID: 2, Time: time.Now()}).Encode(),
+	}
+
+	if since != nil {
+		changes.Deleted = append(changes.Deleted, &models.Tombstone{ID: db.NewID(), DeletedAt: time.Now()})
+	}
+
+	return changes, nil
+}
+
+func (m *TM) Sync(ctx context.Context, userID string, base *models.SyncToken, changes []models.SyncChange) ([]*models.SyncResult, error) {
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	results := make([]*models.SyncResult, 0, len(changes))
+
+	for _, c := range changes {
+		res := &models.SyncResult{TaskID: c.TaskID, Op: c.Op}
+
+		switch c.TaskID {
+		case "1":
+			res.Err = models.ErrRecordNotFound
+		case "410":
+			res.Err = models.ErrTaskGone
+		case "422":
+			res.Err = models.ErrIncompleteTask
+		case "409":
+			if base != nil {
+				res.Conflicts = c.Fields
+			}
+		}
+
+		results = append(results, res)
+	}
+
+	return results, nil
+}
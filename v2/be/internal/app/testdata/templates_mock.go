@@ -0,0 +1,101 @@
+package testdata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+type TPM struct{}
+
+func NewTPM() *TPM {
+	return &TPM{}
+}
+
+func (m *TPM) Create(ctx context.Context, t *models.Template) error {
+	switch t.Name {
+	case "test":
+		return models.ErrDuplicateTemplate
+	case "broken":
+		return models.ErrOpFailed
+	}
+
+	t.CreatedAt = time.Now()
+
+	return nil
+}
+
+func (m *TPM) All(ctx context.Context, userID string) ([]*models.Template, error) {
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	if userID == "204" {
+		return nil, nil
+	}
+
+	t, err := m.GetByID(ctx, db.NewID(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*models.Template{t}, nil
+}
+
+func (m *TPM) GetByID(ctx context.Context, id, userID string) (*models.Template, error) {
+	switch id {
+	case "1":
+		return nil, models.ErrRecordNotFound
+	case "25":
+		return nil, models.ErrOpFailed
+	}
+
+	t := &models.Template{
+		ID:          id,
+		UserID:      userID,
+		Name:        "release",
+		Title:       "Release {{version}} on {{date}}",
+		Description: gofakeit.Sentence(6),
+		Subtasks: []models.TemplateSubtask{
+			{Title: "Tag {{version}}", Description: gofakeit.Sentence(4)},
+		},
+		CreatedAt: time.Now(),
+	}
+
+	return t, nil
+}
+
+func (m *TPM) Delete(ctx context.Context, id, userID string) error {
+	switch id {
+	case "1":
+		return models.ErrRecordNotFound
+	case "25":
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
+
+func (m *TPM) Instantiate(ctx context.Context, id, userID, projectID string, vars map[string]string) (string, error) {
+	switch id {
+	case "1":
+		return "", models.ErrRecordNotFound
+	case "25":
+		return "", models.ErrOpFailed
+	case "409":
+		return "", models.ErrDuplicateTask
+	case "422":
+		return "", fmt.Errorf("%w: version", models.ErrMissingVariable)
+	}
+
+	if projectID == "1" {
+		return "", models.ErrProjectNotFound
+	}
+
+	return db.NewID(), nil
+}
@@ -0,0 +1,35 @@
+package testdata
+
+import (
+	"context"
+
+	"v2/be/internal/models"
+)
+
+type WM struct{}
+
+func NewWM() *WM {
+	return &WM{}
+}
+
+func (m *WM) Get(ctx context.Context, userID string) (*models.Workflow, error) {
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	w := models.DefaultWorkflow
+
+	return &w, nil
+}
+
+func (m *WM) Replace(ctx context.Context, userID string, w *models.Workflow) error {
+	if userID == "25" {
+		return models.ErrOpFailed
+	}
+
+	if userID == "409" {
+		return models.ErrStatusInUse
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package testdata
+
+import (
+	"context"
+	"time"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+type WS struct{}
+
+func NewWS() *WS {
+	return &WS{}
+}
+
+func (m *WS) Create(ctx context.Context, w *models.Workspace, userID string) error {
+	if w.Name == "broken" {
+		return models.ErrOpFailed
+	}
+
+	w.Role = models.WorkspaceOwner
+	w.CreatedAt = time.Now()
+
+	return nil
+}
+
+func (m *WS) All(ctx context.Context, userID string) ([]*models.Workspace, error) {
+	if userID == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	if userID == "204" {
+		return nil, nil
+	}
+
+	w := &models.Workspace{
+		ID:        db.NewID(),
+		Name:      gofakeit.Company(),
+		Role:      models.WorkspaceMember,
+		CreatedAt: time.Now(),
+	}
+
+	return []*models.Workspace{w}, nil
+}
+
+func (m *WS) Role(ctx context.Context, id, userID string) (models.WorkspaceRole, error) {
+	switch id {
+	case "1":
+		return "", models.ErrRecordNotFound
+	case "25":
+		return "", models.ErrOpFailed
+	case "guest":
+		return models.WorkspaceGuest, nil
+	case "member":
+		return models.WorkspaceMember, nil
+	case "admin":
+		return models.WorkspaceAdmin, nil
+	default:
+		return models.WorkspaceOwner, nil
+	}
+}
+
+func (m *WS) Delete(ctx context.Context, id string) error {
+	switch id {
+	case "1":
+		return models.ErrRecordNotFound
+	case "25":
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
+
+func (m *WS) Members(ctx context.Context, id string) ([]*models.Member, error) {
+	if id == "25" {
+		return nil, models.ErrOpFailed
+	}
+
+	if id == "204" {
+		return nil, nil
+	}
+
+	mb := &models.Member{
+		WorkspaceID: id,
+		UserID:      db.NewID(),
+		Username:    gofakeit.Username(),
+		Role:        models.WorkspaceOwner,
+		CreatedAt:   time.Now(),
+	}
+
+	return []*models.Member{mb}, nil
+}
+
+func (m *WS) Invite(ctx context.Context, mb *models.Member) error {
+	switch mb.Username {
+	case "nobody":
+		return models.ErrMemberUserNotFound
+	case "taken":
+		return models.ErrDuplicateMember
+	case "broken":
+		return models.ErrOpFailed
+	}
+
+	mb.UserID = db.NewID()
+	mb.CreatedAt = time.Now()
+
+	return nil
+}
+
+func (m *WS) RemoveMember(ctx context.Context, id, userID string) error {
+	switch userID {
+	case "1":
+		return models.ErrRecordNotFound
+	case "owner":
+		return models.ErrRemoveOwner
+	case "25":
+		return models.ErrOpFailed
+	}
+
+	return nil
+}
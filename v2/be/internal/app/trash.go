@@ -0,0 +1,116 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+
+	"go.uber.org/zap"
+)
+
+type TrashLister interface {
+	Trash(ctx context.Context, userID string) ([]*models.Task, error)
+}
+
+func HandleListTrash(logger *zap.Logger, tl TrashLister) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		tasks, err := tl.Trash(r.Context(), id)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		if tasks == nil {
+			tasks = []*models.Task{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": tasks})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TaskRestorer interface {
+	Restore(ctx context.Context, id, userID string) error
+}
+
+func HandleRestoreTask(logger *zap.Logger, tr TaskRestorer) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		userID := GetUserID(r)
+
+		err := tr.Restore(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			case errors.Is(err, models.ErrDuplicateTask):
+				DuplicateDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": id})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TrashEmptier interface {
+	EmptyTrash(ctx context.Context, userID string) (int64, error)
+}
+
+func HandleEmptyTrash(logger *zap.Logger, te TrashEmptier) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		n, err := te.EmptyTrash(r.Context(), id)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": n})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TrashPurger interface {
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PurgeTrash removes tasks that have been in the trash for longer than
+// retention, checking every interval until ctx is done
+func PurgeTrash(ctx context.Context, logger *zap.Logger, tp TrashPurger, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := tp.Purge(ctx, time.Now().Add(-retention))
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error("trash purge failed", zap.Error(err))
+		}
+
+		if n > 0 {
+			logger.Info("trash purged", zap.Int64("tasks", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
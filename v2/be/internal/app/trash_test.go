@@ -0,0 +1,133 @@
+package app_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandleListTrash(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		code int
+	}{
+		{name: "valid", uid: db.NewID(), code: http.StatusOK},
+		{name: "empty", uid: "1", code: http.StatusOK},
+		{name: "list error", uid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			h := app.HandleListTrash(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusOK {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), "payload")
+			}
+		})
+	}
+}
+
+func TestHandleRestoreTask(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		code int
+	}{
+		{name: "valid", tid: db.NewID(), code: http.StatusOK},
+		{name: "not in trash", tid: "1", code: http.StatusNotFound},
+		{name: "duplicate open task", tid: "409", code: http.StatusConflict},
+		{name: "restore error", tid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			h := app.HandleRestoreTask(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleEmptyTrash(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		code int
+	}{
+		{name: "valid", uid: db.NewID(), code: http.StatusOK},
+		{name: "empty error", uid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+
+			h := app.HandleEmptyTrash(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := &testdata.Purger{Cancel: cancel}
+
+	done := make(chan struct{})
+	go func() {
+		app.PurgeTrash(ctx, zap.NewNop(), p, time.Hour, time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purge loop did not stop")
+	}
+
+	require.Equal(t, 2, p.Calls)
+}
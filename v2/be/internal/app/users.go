@@ -19,7 +19,11 @@ type UserCreater interface {
 	Create(ctx context.Context, u *models.User) error
 }
 
-func HandleSignup(logger *zap.Logger, sessions *scs.SessionManager, uc UserCreater) http.HandlerFunc {
+type InboxCreater interface {
+	CreateInbox(ctx context.Context, userID string) (string, error)
+}
+
+func HandleSignup(logger *zap.Logger, sessions *scs.SessionManager, uc UserCreater, ic InboxCreater) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var input struct {
 			Username string `json:"username"`
@@ -71,6 +75,12 @@ func HandleSignup(logger *zap.Logger, sessions *scs.SessionManager, uc UserCreat
 			return
 		}
 
+		_, err = ic.CreateInbox(r.Context(), u.ID)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
 		sessions.Put(r.Context(), authenticatedUser, u.ID)
 
 		err = parser.Write(w, http.StatusCreated, parser.Envelope{"payload": u.ID})
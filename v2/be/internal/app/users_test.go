@@ -23,7 +23,7 @@ func TestHandleSignup(t *testing.T) {
 		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer([]byte(`{"username": "alex", "password": "R#L:>t^9N?%o"}`)))
 
 		sessions := scs.New()
-		h := app.HandleSignup(zap.NewNop(), sessions, testdata.NewUM())
+		h := app.HandleSignup(zap.NewNop(), sessions, testdata.NewUM(), testdata.NewPM())
 
 		sessions.LoadAndSave(h).ServeHTTP(rr, r)
 
@@ -76,7 +76,7 @@ func TestHandleSignup(t *testing.T) {
 
 				sessions := scs.New()
 
-				h := app.HandleSignup(zap.NewNop(), sessions, testdata.NewUM())
+				h := app.HandleSignup(zap.NewNop(), sessions, testdata.NewUM(), testdata.NewPM())
 
 				sessions.LoadAndSave(h).ServeHTTP(rr, r)
 
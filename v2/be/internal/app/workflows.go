@@ -0,0 +1,189 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+	"v2/be/internal/validator"
+
+	"go.uber.org/zap"
+)
+
+// validateWorkflow checks that w has uniquely named statuses, exactly one of
+// them done and at least one open, and transitions between listed statuses only
+func validateWorkflow(w *models.Workflow, v *validator.Validator) {
+	names := make(map[string]bool, len(w.Statuses))
+	done, open := 0, 0
+
+	for i, s := range w.Statuses {
+		name := strings.TrimSpace(s.Name)
+		if name == "" {
+			v.AddError("statuses", validator.Required)
+			continue
+		}
+
+		v.Check(!names[name], "statuses", "status names must be unique")
+		names[name] = true
+		w.Statuses[i].Name = name
+
+		if s.Done {
+			done++
+		} else {
+			open++
+		}
+	}
+
+	v.Check(done == 1, "statuses", "exactly one status must be done")
+	v.Check(open > 0, "statuses", "at least one status must be open")
+
+	for i, t := range w.Transitions {
+		from, to := strings.TrimSpace(t.From), strings.TrimSpace(t.To)
+
+		v.Check(names[from] && names[to], "transitions", "transitions must use listed statuses")
+		v.Check(from != to, "transitions", "a status cannot transition to itself")
+
+		w.Transitions[i] = models.Transition{From: from, To: to}
+	}
+}
+
+type WorkflowGetter interface {
+	Get(ctx context.Context, userID string) (*models.Workflow, error)
+}
+
+func HandleGetWorkflow(logger *zap.Logger, wg WorkflowGetter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		wf, err := wg.Get(r.Context(), id)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": wf})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type WorkflowReplacer interface {
+	Replace(ctx context.Context, userID string, w *models.Workflow) error
+}
+
+// HandleUpdateWorkflow replaces the statuses and transitions of the user's board
+func HandleUpdateWorkflow(logger *zap.Logger, wr WorkflowReplacer) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		var input models.Workflow
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		v := validator.New()
+		validateWorkflow(&input, v)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		err = wr.Replace(r.Context(), id, &input)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrStatusInUse):
+				ConflictingStateError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": input})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type TaskStatusSetter interface {
+	TaskGetter
+	SetStatus(ctx context.Context, id, userID, status string) error
+}
+
+// HandleSetTaskStatus moves a task to another status along an allowed transition of the user's workflow
+func HandleSetTaskStatus(logger *zap.Logger, ts TaskStatusSetter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetTaskID(r)
+		userID := GetOwnerID(r)
+
+		var input struct {
+			Status string `json:"status"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.Status = strings.TrimSpace(input.Status)
+
+		v := validator.New()
+		v.RequiredString(input.Status, "status", validator.Required)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		t, err := ts.GetByID(r.Context(), id, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		if t.Status == input.Status {
+			err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t.ID})
+			if err != nil {
+				writeError(w)
+			}
+			return
+		}
+
+		err = ts.SetStatus(r.Context(), t.ID, userID, input.Status)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			case errors.Is(err, models.ErrStatusNotFound):
+				InvalidDataError(w, map[string]string{"status": err.Error()})
+			case errors.Is(err, models.ErrInvalidTransition),
+				errors.Is(err, models.ErrOpenBlockers),
+				errors.Is(err, models.ErrOpenSubtasks):
+				ConflictingStateError(w, logger, err)
+			case errors.Is(err, models.ErrDuplicateTask):
+				DuplicateDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": t.ID})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package app_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandleGetWorkflow(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		code int
+	}{
+		{name: "valid", uid: db.NewID(), code: http.StatusOK},
+		{name: "get error", uid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			h := app.HandleGetWorkflow(zap.NewNop(), testdata.NewWM())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusOK {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), "in_progress")
+			}
+		})
+	}
+}
+
+func TestHandleUpdateWorkflow(t *testing.T) {
+	valid := `{"statuses": [{"name": "todo"}, {"name": "doing"}, {"name": "done", "done": true}],
+		"transitions": [{"from": "todo", "to": "doing"}, {"from": "doing", "to": "done"}]}`
+
+	tests := []struct {
+		name string
+		uid  string
+		body string
+		code int
+	}{
+		{name: "valid", uid: db.NewID(), body: valid, code: http.StatusOK},
+		{name: "bad body", uid: db.NewID(), body: `{"columns": []}`, code: http.StatusBadRequest},
+		{
+			name: "no done status",
+			uid:  db.NewID(),
+			body: `{"statuses": [{"name": "todo"}], "transitions": []}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "two done statuses",
+			uid:  db.NewID(),
+			body: `{"statuses": [{"name": "todo"}, {"name": "done", "done": true}, {"name": "shipped", "done": true}]}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "only done status",
+			uid:  db.NewID(),
+			body: `{"statuses": [{"name": "done", "done": true}]}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "duplicate status",
+			uid:  db.NewID(),
+			body: `{"statuses": [{"name": "todo"}, {"name": "todo"}, {"name": "done", "done": true}]}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "unknown transition status",
+			uid:  db.NewID(),
+			body: `{"statuses": [{"name": "todo"}, {"name": "done", "done": true}], "transitions": [{"from": "todo", "to": "review"}]}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		{name: "status in use", uid: "409", body: valid, code: http.StatusConflict},
+		{name: "replace error", uid: "25", body: valid, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(tt.body))
+
+			h := app.HandleUpdateWorkflow(zap.NewNop(), testdata.NewWM())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleSetTaskStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  string
+		body string
+		code int
+	}{
+		{name: "valid", tid: db.NewID(), body: `{"status": "in_progress"}`, code: http.StatusOK},
+		{name: "bad body", tid: db.NewID(), body: `{"state": "done"}`, code: http.StatusBadRequest},
+		{name: "empty status", tid: db.NewID(), body: `{"status": " "}`, code: http.StatusUnprocessableEntity},
+		{name: "missing task", tid: "1", body: `{"status": "done"}`, code: http.StatusNotFound},
+		{name: "same status", tid: db.NewID(), body: `{"status": "todo"}`, code: http.StatusOK},
+		{name: "unknown status", tid: db.NewID(), body: `{"status": "unknown"}`, code: http.StatusUnprocessableEntity},
+		{name: "transition not allowed", tid: db.NewID(), body: `{"status": "review"}`, code: http.StatusConflict},
+		{name: "open blockers", tid: db.NewID(), body: `{"status": "blocked"}`, code: http.StatusConflict},
+		{name: "set error", tid: db.NewID(), body: `{"status": "broken"}`, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPatch, "/", bytes.NewBufferString(tt.body))
+			r = r.WithContext(setTaskID(t, tt.tid))
+
+			h := app.HandleSetTaskStatus(zap.NewNop(), testdata.NewTM())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
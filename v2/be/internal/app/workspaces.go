@@ -0,0 +1,223 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+	"v2/be/internal/parser"
+	"v2/be/internal/validator"
+
+	"go.uber.org/zap"
+)
+
+type WorkspaceCreater interface {
+	Create(ctx context.Context, w *models.Workspace, userID string) error
+}
+
+// HandleCreateWorkspace adds a workspace owned by the user
+func HandleCreateWorkspace(logger *zap.Logger, wc WorkspaceCreater) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		var input struct {
+			Name string `json:"name"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.Name = parser.Sanitize(input.Name)
+
+		v := validator.New()
+		v.RequiredString(input.Name, "name", validator.Required)
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		ws := &models.Workspace{
+			ID:   db.NewID(),
+			Name: input.Name,
+		}
+
+		err = wc.Create(r.Context(), ws, id)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		err = parser.Write(w, http.StatusCreated, parser.Envelope{"payload": ws})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type WorkspaceLister interface {
+	All(ctx context.Context, userID string) ([]*models.Workspace, error)
+}
+
+// HandleListWorkspaces returns the workspaces the user belongs to along with their role in each
+func HandleListWorkspaces(logger *zap.Logger, wl WorkspaceLister) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetUserID(r)
+
+		workspaces, err := wl.All(r.Context(), id)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		if workspaces == nil {
+			workspaces = []*models.Workspace{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": workspaces})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type WorkspaceDeleter interface {
+	Delete(ctx context.Context, id string) error
+}
+
+func HandleDeleteWorkspace(logger *zap.Logger, wd WorkspaceDeleter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetWorkspaceID(r)
+
+		err := wd.Delete(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": "deleted"})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type MemberLister interface {
+	Members(ctx context.Context, id string) ([]*models.Member, error)
+}
+
+func HandleListMembers(logger *zap.Logger, ml MemberLister) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetWorkspaceID(r)
+
+		members, err := ml.Members(r.Context(), id)
+		if err != nil {
+			ServerError(w, logger, err)
+			return
+		}
+
+		if members == nil {
+			members = []*models.Member{}
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": members})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type MemberInviter interface {
+	Invite(ctx context.Context, m *models.Member) error
+}
+
+// HandleInviteMember adds another user to the workspace by username
+func HandleInviteMember(logger *zap.Logger, mi MemberInviter) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetWorkspaceID(r)
+
+		var input struct {
+			Username string `json:"username"`
+			Role     string `json:"role"`
+		}
+
+		err := parser.Read(w, r, &input)
+		if err != nil {
+			ReadError(w, logger, err)
+			return
+		}
+
+		input.Username = strings.TrimSpace(input.Username)
+
+		v := validator.New()
+		v.RequiredString(input.Username, "username", validator.Required)
+		v.Check(validator.PermittedValue(input.Role, models.InviteRoles...), "role", "invalid role value")
+		if !v.Valid() {
+			InvalidDataError(w, v.Errors())
+			return
+		}
+
+		m := &models.Member{
+			WorkspaceID: id,
+			Username:    input.Username,
+			Role:        models.WorkspaceRole(input.Role),
+		}
+
+		err = mi.Invite(r.Context(), m)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrMemberUserNotFound):
+				InvalidDataError(w, map[string]string{"username": err.Error()})
+			case errors.Is(err, models.ErrDuplicateMember):
+				DuplicateDataError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusCreated, parser.Envelope{"payload": m})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
+
+type MemberRemover interface {
+	RemoveMember(ctx context.Context, id, userID string) error
+}
+
+func HandleRemoveMember(logger *zap.Logger, mr MemberRemover) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetWorkspaceID(r)
+		memberID := GetMemberID(r)
+
+		err := mr.RemoveMember(r.Context(), id, memberID)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecordNotFound):
+				MissingDataError(w, logger, err)
+			case errors.Is(err, models.ErrRemoveOwner):
+				ConflictingStateError(w, logger, err)
+			default:
+				ServerError(w, logger, err)
+			}
+			return
+		}
+
+		err = parser.Write(w, http.StatusOK, parser.Envelope{"payload": "removed"})
+		if err != nil {
+			writeError(w)
+		}
+	})
+}
@@ -0,0 +1,250 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"v2/be/internal/app"
+	"v2/be/internal/app/testdata"
+	"v2/be/internal/db"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setWorkspaceID(t *testing.T, id string) context.Context {
+	t.Helper()
+
+	rtx := chi.NewRouteContext()
+	rtx.URLParams.Add("workspace_id", id)
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rtx)
+
+	return ctx
+}
+
+func setMemberID(t *testing.T, workspaceID, id string) context.Context {
+	t.Helper()
+
+	rtx := chi.NewRouteContext()
+	rtx.URLParams.Add("workspace_id", workspaceID)
+	rtx.URLParams.Add("member_id", id)
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rtx)
+
+	return ctx
+}
+
+func TestHandleCreateWorkspace(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{name: "valid", body: `{"name": "platform"}`, code: http.StatusCreated},
+		{name: "bad body", body: `{"title": "platform"}`, code: http.StatusBadRequest},
+		{name: "empty name", body: `{"name": " "}`, code: http.StatusUnprocessableEntity},
+		{name: "create error", body: `{"name": "broken"}`, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.body))
+
+			h := app.HandleCreateWorkspace(zap.NewNop(), testdata.NewWS())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusCreated {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), `"role":"owner"`)
+			}
+		})
+	}
+}
+
+func TestHandleListWorkspaces(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		want string
+		code int
+	}{
+		{name: "valid", uid: db.NewID(), want: `"role":"member"`, code: http.StatusOK},
+		{name: "no workspaces", uid: "204", want: `"payload":[]`, code: http.StatusOK},
+		{name: "list error", uid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			h := app.HandleListWorkspaces(zap.NewNop(), testdata.NewWS())
+
+			session := scs.New()
+			m := lsm(t, session, tt.uid)
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusOK {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleDeleteWorkspace(t *testing.T) {
+	tests := []struct {
+		name string
+		wid  string
+		code int
+	}{
+		{name: "valid", wid: db.NewID(), code: http.StatusOK},
+		{name: "missing workspace", wid: "1", code: http.StatusNotFound},
+		{name: "delete error", wid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			r = r.WithContext(setWorkspaceID(t, tt.wid))
+
+			h := app.HandleDeleteWorkspace(zap.NewNop(), testdata.NewWS())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleListMembers(t *testing.T) {
+	tests := []struct {
+		name string
+		wid  string
+		want string
+		code int
+	}{
+		{name: "valid", wid: db.NewID(), want: `"role":"owner"`, code: http.StatusOK},
+		{name: "no members", wid: "204", want: `"payload":[]`, code: http.StatusOK},
+		{name: "list error", wid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(setWorkspaceID(t, tt.wid))
+
+			h := app.HandleListMembers(zap.NewNop(), testdata.NewWS())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+
+			if tt.code == http.StatusOK {
+				rs := rr.Result()
+				defer rs.Body.Close()
+
+				require.Contains(t, readTestBody(t, rs.Body), tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleInviteMember(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{name: "valid", body: `{"username": "ada", "role": "member"}`, code: http.StatusCreated},
+		{name: "bad body", body: `{"user": "ada"}`, code: http.StatusBadRequest},
+		{name: "no username", body: `{"username": " ", "role": "member"}`, code: http.StatusUnprocessableEntity},
+		{name: "owner role", body: `{"username": "ada", "role": "owner"}`, code: http.StatusUnprocessableEntity},
+		{name: "unknown user", body: `{"username": "nobody", "role": "guest"}`, code: http.StatusUnprocessableEntity},
+		{name: "already member", body: `{"username": "taken", "role": "admin"}`, code: http.StatusConflict},
+		{name: "invite error", body: `{"username": "broken", "role": "member"}`, code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.body))
+			r = r.WithContext(setWorkspaceID(t, db.NewID()))
+
+			h := app.HandleInviteMember(zap.NewNop(), testdata.NewWS())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestHandleRemoveMember(t *testing.T) {
+	tests := []struct {
+		name string
+		mid  string
+		code int
+	}{
+		{name: "valid", mid: db.NewID(), code: http.StatusOK},
+		{name: "not a member", mid: "1", code: http.StatusNotFound},
+		{name: "owner", mid: "owner", code: http.StatusConflict},
+		{name: "remove error", mid: "25", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			r = r.WithContext(setMemberID(t, db.NewID(), tt.mid))
+
+			h := app.HandleRemoveMember(zap.NewNop(), testdata.NewWS())
+
+			session := scs.New()
+			m := lsm(t, session, db.NewID())
+
+			session.LoadAndSave(m(h)).ServeHTTP(rr, r)
+			require.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// FS stores blobs as files in a single directory, named after their key
+type FS struct {
+	Root string
+}
+
+// NewFS returns a store writing under root, creating the directory when missing
+func NewFS(root string) (*FS, error) {
+	err := os.MkdirAll(root, 0o750)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FS{Root: root}, nil
+}
+
+// path returns the file backing key. Keys are plain names, never paths.
+func (s *FS) path(key string) (string, error) {
+	if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.Root, key), nil
+}
+
+// Put writes r under key and returns the number of bytes written. The blob only
+// becomes visible once fully written, and nothing is kept when r fails.
+func (s *FS) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.CreateTemp(s.Root, ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+
+	defer os.Remove(f.Name())
+
+	n, err := io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	err = os.Rename(f.Name(), path)
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Open returns the content stored under key
+func (s *FS) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+// Delete removes the blob stored under key. Removing a missing blob is not an error.
+func (s *FS) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
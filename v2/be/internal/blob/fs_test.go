@@ -0,0 +1,72 @@
+package blob_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"v2/be/internal/blob"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFS(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := blob.NewFS(t.TempDir())
+		require.NoError(t, err)
+
+		n, err := s.Put(context.Background(), "report", strings.NewReader("weekly"))
+		require.NoError(t, err)
+		require.Equal(t, int64(6), n)
+
+		rc, err := s.Open(context.Background(), "report")
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		require.Equal(t, "weekly", string(b))
+
+		require.NoError(t, s.Delete(context.Background(), "report"))
+		require.NoError(t, s.Delete(context.Background(), "report"))
+
+		_, err = s.Open(context.Background(), "report")
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("failed write", func(t *testing.T) {
+		t.Parallel()
+
+		root := t.TempDir()
+
+		s, err := blob.NewFS(root)
+		require.NoError(t, err)
+
+		r := io.MultiReader(strings.NewReader("half"), iotest.ErrReader(errors.New("read failed")))
+
+		_, err = s.Put(context.Background(), "broken", r)
+		require.Error(t, err)
+
+		entries, err := os.ReadDir(root)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := blob.NewFS(t.TempDir())
+		require.NoError(t, err)
+
+		for _, key := range []string{"", ".", "..", "../etc", `a\b`} {
+			_, err = s.Put(context.Background(), key, strings.NewReader("x"))
+			require.ErrorIs(t, err, blob.ErrInvalidKey)
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package models
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Role is the access a user has to a task. Owners can do anything, editors
+// can change the task, assignees can complete it and viewers can only read it.
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleAssignee Role = "assignee"
+	RoleEditor   Role = "editor"
+	RoleOwner    Role = "owner"
+)
+
+// ShareRoles lists the roles an owner can grant to another user
+var ShareRoles = []string{string(RoleViewer), string(RoleEditor)}
+
+var roleRanks = map[Role]int{
+	RoleViewer:   1,
+	RoleAssignee: 2,
+	RoleEditor:   3,
+	RoleOwner:    4,
+}
+
+// Allows reports whether r grants at least the access of min
+func (r Role) Allows(min Role) bool {
+	return roleRanks[r] >= roleRanks[min]
+}
+
+// Access is what a user may do with a task, who owns it and the workspace it
+// belongs to. TasksModel scopes its queries to the owner, so callers act on
+// shared tasks with OwnerID. Other models check access with authorizeTask.
+type Access struct {
+	OwnerID     string  `json:"owner_id"`
+	WorkspaceID *string `json:"workspace_id"`
+	Role        Role    `json:"role"`
+}
+
+// sharedTasks selects the ids of the tasks shared with the user bound to param,
+// directly, through a shared ancestor or through a shared project. It is the
+// set form of the share lookup in taskAccess, for listing tasks.
+func sharedTasks(param string) string {
+	return `WITH RECURSIVE shared AS (
+		SELECT t.id FROM tasks t JOIN shares s ON s.task_id = t.id OR s.project_id = t.project_id
+		WHERE s.user_id = ` + param + `
+		UNION
+		SELECT t.id FROM tasks t JOIN shared ON t.parent_id = shared.id
+	)
+	SELECT id FROM shared`
+}
+
+type actorKey struct{}
+
+// WithActor marks userID as the user acting in ctx. Task history credits the
+// actor rather than the owner the models are scoped to.
+func WithActor(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// actor returns the user acting in ctx, or fallback when none was set
+func actor(ctx context.Context, fallback string) string {
+	if id, ok := ctx.Value(actorKey{}).(string); ok {
+		return id
+	}
+
+	return fallback
+}
+
+// AccessModel resolves what users may do with tasks they own or that were shared with them
+type AccessModel struct {
+	Pool *pgxpool.Pool
+}
+
+// Task returns the access the user has to a task, failing with
+// ErrRecordNotFound when the task is missing or neither owned nor shared
+func (m *AccessModel) Task(ctx context.Context, taskID, userID string) (*Access, error) {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	a, err := taskAccess(ctx, tx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Attachment returns the access the user has to the task an attachment belongs to
+func (m *AccessModel) Attachment(ctx context.Context, id, userID string) (*Access, error) {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var taskID string
+
+	err = tx.QueryRow(ctx, `SELECT task_id FROM attachments WHERE id = $1`, id).Scan(&taskID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	a, err := taskAccess(ctx, tx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// authorizeTask returns the access the user has to a task, failing with
+// ErrRecordNotFound unless it allows at least role. Models that act for the
+// user rather than for the task's owner check access through it.
+func authorizeTask(ctx context.Context, tx pgx.Tx, taskID, userID string, role Role) (*Access, error) {
+	a, err := taskAccess(ctx, tx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.Role.Allows(role) {
+		return nil, ErrRecordNotFound
+	}
+
+	return a, nil
+}
+
+// taskAccess does the work of Task. A share on a task also covers its subtasks,
+// and the strongest of the shares reaching a task, of the assignment to it and
+// of the role in its workspace wins.
+func taskAccess(ctx context.Context, tx pgx.Tx, taskID, userID string) (*Access, error) {
+	query := `WITH RECURSIVE lineage AS (
+		SELECT id, parent_id FROM tasks WHERE id = $1
+		UNION ALL
+		SELECT t.id, t.parent_id FROM tasks t JOIN lineage l ON t.id = l.parent_id
+	)
+	SELECT tasks.user_id, tasks.workspace_id,
+		CASE WHEN tasks.user_id = $2 THEN 'owner'
+		ELSE (SELECT max(s.role)::TEXT FROM shares s
+			WHERE s.user_id = $2 AND (s.task_id IN (SELECT id FROM lineage) OR s.project_id = tasks.project_id))
+		END,
+		tasks.assignee_id IS NOT DISTINCT FROM $2,
+		(SELECT m.role::TEXT FROM workspace_members m WHERE m.workspace_id = tasks.workspace_id AND m.user_id = $2)
+	FROM tasks
+	WHERE tasks.id = $1 AND tasks.deleted_at IS NULL`
+
+	var a Access
+	var role *string
+	var assigned bool
+	var member *WorkspaceRole
+
+	err := tx.QueryRow(ctx, query, taskID, userID).Scan(&a.OwnerID, &a.WorkspaceID, &role, &assigned, &member)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if role != nil {
+		a.Role = Role(*role)
+	}
+
+	if assigned && !a.Role.Allows(RoleAssignee) {
+		a.Role = RoleAssignee
+	}
+
+	if member != nil && !a.Role.Allows(member.taskRole()) {
+		a.Role = member.taskRole()
+	}
+
+	if a.Role == "" {
+		return nil, ErrRecordNotFound
+	}
+
+	return &a, nil
+}
@@ -0,0 +1,120 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleAllows(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, models.RoleOwner.Allows(models.RoleEditor))
+	require.True(t, models.RoleEditor.Allows(models.RoleEditor))
+	require.True(t, models.RoleEditor.Allows(models.RoleAssignee))
+	require.False(t, models.RoleAssignee.Allows(models.RoleEditor))
+	require.False(t, models.RoleViewer.Allows(models.RoleEditor))
+	require.False(t, models.Role("").Allows(models.RoleViewer))
+}
+
+func TestAccess(t *testing.T) {
+	t.Run("task share", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		owner := testUser(t, users)
+		other := testUser(t, users)
+		stranger := testUser(t, users)
+
+		tasks := &models.TasksModel{Pool: pool}
+		parent := testSubtask(t, tasks, owner.ID, nil)
+		child := testSubtask(t, tasks, owner.ID, &parent.ID)
+
+		shares := &models.SharesModel{Pool: pool}
+		s := &models.Share{ID: db.NewID(), OwnerID: owner.ID, Username: other.Username, TaskID: &parent.ID, Role: models.RoleEditor}
+		require.NoError(t, shares.Create(context.Background(), s))
+
+		access := &models.AccessModel{Pool: pool}
+
+		a, err := access.Task(context.Background(), parent.ID, owner.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.RoleOwner, a.Role)
+
+		a, err = access.Task(context.Background(), child.ID, other.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.RoleEditor, a.Role)
+		require.Equal(t, owner.ID, a.OwnerID)
+
+		_, err = access.Task(context.Background(), parent.ID, stranger.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+
+		all, _, err := tasks.All(context.Background(), other.ID, models.TaskFilter{})
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+	})
+
+	t.Run("project share", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		owner := testUser(t, users)
+		other := testUser(t, users)
+
+		projects := &models.ProjectsModel{Pool: pool}
+		p := &models.Project{ID: db.NewID(), UserID: owner.ID, Name: "work"}
+		require.NoError(t, projects.Create(context.Background(), p))
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := &models.Task{ID: db.NewID(), UserID: owner.ID, ProjectID: p.ID, Title: "plan", Description: gofakeit.Phrase()}
+		require.NoError(t, tasks.Create(context.Background(), task))
+
+		shares := &models.SharesModel{Pool: pool}
+		s := &models.Share{ID: db.NewID(), OwnerID: owner.ID, Username: other.Username, ProjectID: &p.ID, Role: models.RoleViewer}
+		require.NoError(t, shares.Create(context.Background(), s))
+
+		access := &models.AccessModel{Pool: pool}
+
+		a, err := access.Task(context.Background(), task.ID, other.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.RoleViewer, a.Role)
+
+		require.NoError(t, shares.Delete(context.Background(), s.ID, owner.ID))
+
+		_, err = access.Task(context.Background(), task.ID, other.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+
+	t.Run("attachment", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		owner := testUser(t, users)
+		other := testUser(t, users)
+
+		task := testSubtask(t, &models.TasksModel{Pool: pool}, owner.ID, nil)
+
+		shares := &models.SharesModel{Pool: pool}
+		s := &models.Share{ID: db.NewID(), OwnerID: owner.ID, Username: other.Username, TaskID: &task.ID, Role: models.RoleEditor}
+		require.NoError(t, shares.Create(context.Background(), s))
+
+		attachments := &models.AttachmentsModel{Pool: pool, Blobs: testBlobs(t)}
+		at := testAttachment(t, attachments, task.ID, other.ID, "shared notes")
+
+		access := &models.AccessModel{Pool: pool}
+
+		a, err := access.Attachment(context.Background(), at.ID, other.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.RoleEditor, a.Role)
+
+		_, err = access.Attachment(context.Background(), db.NewID(), other.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+}
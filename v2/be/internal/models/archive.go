@@ -0,0 +1,142 @@
+package models
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrTaskNotCompleted = errors.New("only completed tasks can be archived")
+	ErrTaskArchived     = errors.New("task is already archived")
+)
+
+// Archive moves a completed task and its completed subtasks to the archive,
+// out of the default listing. Reopening the task takes it back out.
+func (m *TasksModel) Archive(ctx context.Context, id, userID string) error {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var completed, archived bool
+
+	err = tx.QueryRow(ctx, `SELECT completed, archived_at IS NOT NULL FROM tasks
+	WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`, id, userID).Scan(&completed, &archived)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	switch {
+	case archived:
+		return ErrTaskArchived
+	case !completed:
+		return ErrTaskNotCompleted
+	}
+
+	_, err = archiveTask(ctx, tx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ArchiveCompleted moves every completed task of the user to the archive and
+// returns how many tasks were archived
+func (m *TasksModel) ArchiveCompleted(ctx context.Context, userID string) (int64, error) {
+	query := `SELECT id FROM tasks
+	WHERE user_id = $1 AND completed AND archived_at IS NULL AND deleted_at IS NULL
+	ORDER BY id`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+
+	for _, id := range ids {
+		n, aerr := archiveTask(ctx, tx, id, userID)
+		if aerr != nil {
+			return 0, aerr
+		}
+
+		total += n
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// archiveTask archives a task with its completed subtasks and returns how many
+// tasks were archived, none when an ancestor already took it into the archive.
+func archiveTask(ctx context.Context, tx pgx.Tx, id, userID string) (int64, error) {
+	query := `WITH RECURSIVE subtree AS (
+		SELECT id FROM tasks WHERE id = $1 AND user_id = $2
+		UNION ALL
+		SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+		WHERE t.deleted_at IS NULL
+	)
+	UPDATE tasks
+	SET archived_at = now()
+	WHERE id IN (SELECT id FROM subtree) AND completed AND archived_at IS NULL AND deleted_at IS NULL`
+
+	before, err := snapshotTask(ctx, tx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(ctx, query, id, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if result.RowsAffected() == 0 {
+		return 0, nil
+	}
+
+	err = recordTaskEvent(ctx, tx, id, userID, TaskUpdated, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
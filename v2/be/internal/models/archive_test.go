@@ -0,0 +1,118 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTasksArchive(t *testing.T) {
+	t.Run("archive and reopen", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		parent := testSubtask(t, tasks, u.ID, nil)
+		child := testSubtask(t, tasks, u.ID, &parent.ID)
+
+		err := tasks.Archive(context.Background(), parent.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrTaskNotCompleted)
+
+		require.NoError(t, tasks.Complete(context.Background(), parent.ID, u.ID, 0, true))
+		require.NoError(t, tasks.Archive(context.Background(), parent.ID, u.ID))
+
+		err = tasks.Archive(context.Background(), parent.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrTaskArchived)
+
+		listed, _, err := tasks.All(context.Background(), u.ID, models.TaskFilter{})
+		require.NoError(t, err)
+		require.Empty(t, listed)
+
+		archived, _, err := tasks.All(context.Background(), u.ID, models.TaskFilter{Archived: true})
+		require.NoError(t, err)
+		require.Len(t, archived, 2)
+
+		matches, err := tasks.Search(context.Background(), u.ID, parent.Title, 10)
+		require.NoError(t, err)
+		require.NotEmpty(t, matches)
+
+		events, err := tasks.History(context.Background(), parent.ID, u.ID)
+		require.NoError(t, err)
+		require.Contains(t, events[len(events)-1].Changes, "archived_at")
+
+		require.NoError(t, tasks.Reopen(context.Background(), child.ID, u.ID))
+
+		rt, err := tasks.GetByID(context.Background(), parent.ID, u.ID)
+		require.NoError(t, err)
+		require.Nil(t, rt.ArchivedAt)
+	})
+
+	t.Run("archive completed", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		done := testSubtask(t, tasks, u.ID, nil)
+		open := testSubtask(t, tasks, u.ID, nil)
+
+		require.NoError(t, tasks.Complete(context.Background(), done.ID, u.ID, 0, false))
+
+		n, err := tasks.ArchiveCompleted(context.Background(), u.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, n)
+
+		listed, _, err := tasks.All(context.Background(), u.ID, models.TaskFilter{})
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		require.Equal(t, open.ID, listed[0].ID)
+
+		n, err = tasks.ArchiveCompleted(context.Background(), u.ID)
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+
+	t.Run("title reuse", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		old := testSubtask(t, tasks, u.ID, nil)
+
+		require.NoError(t, tasks.Complete(context.Background(), old.ID, u.ID, 0, false))
+		require.NoError(t, tasks.Archive(context.Background(), old.ID, u.ID))
+
+		task := &models.Task{
+			ID:          db.NewID(),
+			UserID:      u.ID,
+			Title:       old.Title,
+			Description: gofakeit.Phrase(),
+		}
+		require.NoError(t, tasks.Create(context.Background(), task))
+
+		err := tasks.Reopen(context.Background(), old.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrDuplicateTask)
+	})
+
+	t.Run("missing task", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+
+		err := tasks.Archive(context.Background(), db.NewID(), u.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"v2/be/internal/db"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrAssigneeNotFound = errors.New("assignee not found")
+
+// Assign makes assigneeID responsible for a task, or leaves the task
+// unassigned when assigneeID is nil. The assignee must be an existing user.
+func (m *TasksModel) Assign(ctx context.Context, id, userID string, assigneeID *string) error {
+	query := `UPDATE tasks SET assignee_id = $1
+	WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL`
+
+	args := []any{assigneeID, id, userID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	before, err := snapshotTask(ctx, tx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrOpFailed):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "tasks_assignee_id_fkey"):
+			return ErrAssigneeNotFound
+		default:
+			return err
+		}
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrRecordNotFound
+	}
+
+	err = recordTaskEvent(ctx, tx, id, userID, TaskUpdated, before)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTasksAssign(t *testing.T) {
+	t.Run("assign and unassign", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		owner := testUser(t, users)
+		other := testUser(t, users)
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, owner.ID, nil)
+
+		require.NoError(t, tasks.Assign(context.Background(), task.ID, owner.ID, &other.ID))
+
+		rt, err := tasks.GetByID(context.Background(), task.ID, owner.ID)
+		require.NoError(t, err)
+		require.Equal(t, &other.ID, rt.AssigneeID)
+
+		assigned, _, err := tasks.All(context.Background(), other.ID, models.TaskFilter{Assigned: true})
+		require.NoError(t, err)
+		require.Len(t, assigned, 1)
+		require.Equal(t, task.ID, assigned[0].ID)
+
+		mine, _, err := tasks.All(context.Background(), other.ID, models.TaskFilter{})
+		require.NoError(t, err)
+		require.Empty(t, mine)
+
+		events, err := tasks.History(context.Background(), task.ID, owner.ID)
+		require.NoError(t, err)
+		require.Contains(t, events[len(events)-1].Changes, "assignee_id")
+
+		require.NoError(t, tasks.Assign(context.Background(), task.ID, owner.ID, nil))
+
+		assigned, _, err = tasks.All(context.Background(), other.ID, models.TaskFilter{Assigned: true})
+		require.NoError(t, err)
+		require.Empty(t, assigned)
+	})
+
+	t.Run("assignee access", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		owner := testUser(t, users)
+		other := testUser(t, users)
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, owner.ID, nil)
+
+		access := &models.AccessModel{Pool: pool}
+
+		_, err := access.Task(context.Background(), task.ID, other.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+
+		require.NoError(t, tasks.Assign(context.Background(), task.ID, owner.ID, &other.ID))
+
+		a, err := access.Task(context.Background(), task.ID, other.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.RoleAssignee, a.Role)
+		require.Equal(t, owner.ID, a.OwnerID)
+
+		shares := &models.SharesModel{Pool: pool}
+		s := &models.Share{ID: db.NewID(), OwnerID: owner.ID, Username: other.Username, TaskID: &task.ID, Role: models.RoleEditor}
+		require.NoError(t, shares.Create(context.Background(), s))
+
+		a, err = access.Task(context.Background(), task.ID, other.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.RoleEditor, a.Role)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		owner := testUser(t, users)
+		other := testUser(t, users)
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, owner.ID, nil)
+
+		unknown := db.NewID()
+		err := tasks.Assign(context.Background(), task.ID, owner.ID, &unknown)
+		require.ErrorIs(t, err, models.ErrAssigneeNotFound)
+
+		err = tasks.Assign(context.Background(), task.ID, other.ID, &other.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+
+		err = tasks.Assign(context.Background(), db.NewID(), owner.ID, &other.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,296 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MaxAttachmentSize is the largest file a single attachment may hold, and
+// AttachmentQuota the total size of the attachments a user may upload
+const (
+	MaxAttachmentSize = 10 << 20
+	AttachmentQuota   = 100 << 20
+)
+
+var ErrQuotaExceeded = errors.New("attachment quota exceeded")
+
+// BlobStore keeps the content of attachments, keyed by attachment id
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type Attachment struct {
+	ID          string    `json:"id"`
+	TaskID      string    `json:"task_id"`
+	UserID      string    `json:"user_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// attachmentColumns lists the columns read by scanAttachment, in scan order
+const attachmentColumns = `attachments.id, attachments.task_id, attachments.user_id, attachments.name,
+	attachments.content_type, attachments.size, attachments.created_at`
+
+func scanAttachment(row pgx.Row) (*Attachment, error) {
+	var a Attachment
+
+	err := row.Scan(
+		&a.ID,
+		&a.TaskID,
+		&a.UserID,
+		&a.Name,
+		&a.ContentType,
+		&a.Size,
+		&a.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+type AttachmentsModel struct {
+	Pool  *pgxpool.Pool
+	Blobs BlobStore
+}
+
+// Create stores the content of r and attaches it to its task, which the
+// uploader must be able to edit. It fails with
+// ErrQuotaExceeded when the upload would take the user past AttachmentQuota,
+// in which case nothing is kept.
+func (m *AttachmentsModel) Create(ctx context.Context, a *Attachment, r io.Reader) error {
+	query := `INSERT INTO attachments (id, task_id, user_id, name, content_type, size)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING created_at`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = authorizeTask(ctx, tx, a.TaskID, a.UserID, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	var used int64
+
+	err = tx.QueryRow(ctx, `SELECT COALESCE(sum(size), 0) FROM attachments WHERE user_id = $1`, a.UserID).Scan(&used)
+	if err != nil {
+		return err
+	}
+
+	a.Size, err = m.Blobs.Put(ctx, a.ID, r)
+	if err != nil {
+		return err
+	}
+
+	// the blob is only kept once the row pointing at it is committed
+	committed := false
+	defer func() {
+		if !committed {
+			m.Blobs.Delete(context.WithoutCancel(ctx), a.ID)
+		}
+	}()
+
+	if used+a.Size > AttachmentQuota {
+		return ErrQuotaExceeded
+	}
+
+	args := []any{a.ID, a.TaskID, a.UserID, a.Name, a.ContentType, a.Size}
+
+	err = tx.QueryRow(ctx, query, args...).Scan(&a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	committed = true
+
+	return nil
+}
+
+// All returns the attachments of a task visible to the user, oldest first
+func (m *AttachmentsModel) All(ctx context.Context, taskID, userID string) ([]*Attachment, error) {
+	query := `SELECT ` + attachmentColumns + `
+	FROM attachments
+	WHERE attachments.task_id = $1
+	ORDER BY attachments.id`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = authorizeTask(ctx, tx, taskID, userID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []*Attachment
+
+	rows, err := tx.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		a, aerr := scanAttachment(rows)
+		if aerr != nil {
+			return nil, aerr
+		}
+
+		attachments = append(attachments, a)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// Open returns an attachment on a task visible to the user along with its content.
+// The caller must close the content.
+func (m *AttachmentsModel) Open(ctx context.Context, id, userID string) (*Attachment, io.ReadCloser, error) {
+	query := `SELECT ` + attachmentColumns + `
+	FROM attachments
+	WHERE attachments.id = $1`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	a, err := scanAttachment(tx.QueryRow(ctx, query, id))
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil, ErrRecordNotFound
+		default:
+			return nil, nil, err
+		}
+	}
+
+	_, err = authorizeTask(ctx, tx, a.TaskID, userID, RoleViewer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := m.Blobs.Open(ctx, a.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a, content, nil
+}
+
+// Delete removes an attachment from a task the user can edit, and then its content
+func (m *AttachmentsModel) Delete(ctx context.Context, id, userID string) error {
+	query := `DELETE FROM attachments
+	WHERE id = $1
+	RETURNING task_id`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var taskID string
+
+	err = tx.QueryRow(ctx, query, id).Scan(&taskID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	_, err = authorizeTask(ctx, tx, taskID, userID, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return m.Blobs.Delete(ctx, id)
+}
+
+// deleteAttachments removes the attachments of the tasks selected by tasksQuery
+// and returns their ids, so their content can be deleted once the caller
+// commits.
+func deleteAttachments(ctx context.Context, tx pgx.Tx, tasksQuery string, args ...any) ([]string, error) {
+	rows, err := tx.Query(ctx, `DELETE FROM attachments
+	WHERE task_id IN (`+tasksQuery+`)
+	RETURNING id`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// deleteBlobs removes the content of attachments whose rows are already gone
+func deleteBlobs(ctx context.Context, blobs BlobStore, ids []string) error {
+	var errs []error
+
+	for _, id := range ids {
+		errs = append(errs, blobs.Delete(ctx, id))
+	}
+
+	return errors.Join(errs...)
+}
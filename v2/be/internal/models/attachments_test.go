@@ -0,0 +1,164 @@
+package models_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"v2/be/internal/blob"
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testBlobs(t *testing.T) *blob.FS {
+	t.Helper()
+
+	blobs, err := blob.NewFS(t.TempDir())
+	require.NoError(t, err)
+
+	return blobs
+}
+
+func testAttachment(t *testing.T, attachments *models.AttachmentsModel, taskID, userID, content string) *models.Attachment {
+	t.Helper()
+
+	a := &models.Attachment{
+		ID:          db.NewID(),
+		TaskID:      taskID,
+		UserID:      userID,
+		Name:        "notes.txt",
+		ContentType: "text/plain; charset=utf-8",
+	}
+
+	err := attachments.Create(context.Background(), a, strings.NewReader(content))
+	require.NoError(t, err)
+
+	return a
+}
+
+func TestAttachments(t *testing.T) {
+	t.Run("upload and download", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		attachments := &models.AttachmentsModel{Pool: pool, Blobs: testBlobs(t)}
+		a := testAttachment(t, attachments, task.ID, u.ID, "weekly notes")
+		require.Equal(t, int64(12), a.Size)
+
+		all, err := attachments.All(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, a.ID, all[0].ID)
+
+		got, content, err := attachments.Open(context.Background(), a.ID, u.ID)
+		require.NoError(t, err)
+		defer content.Close()
+
+		b, err := io.ReadAll(content)
+		require.NoError(t, err)
+		require.Equal(t, "weekly notes", string(b))
+		require.Equal(t, "notes.txt", got.Name)
+
+		require.NoError(t, attachments.Delete(context.Background(), a.ID, u.ID))
+
+		_, _, err = attachments.Open(context.Background(), a.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+
+		_, err = attachments.Blobs.Open(context.Background(), a.ID)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		u := testUser(t, users)
+		other := testUser(t, users)
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		blobs := testBlobs(t)
+		attachments := &models.AttachmentsModel{Pool: pool, Blobs: blobs}
+
+		a := &models.Attachment{ID: db.NewID(), TaskID: task.ID, UserID: other.ID, Name: "x.txt", ContentType: "text/plain"}
+
+		err := attachments.Create(context.Background(), a, strings.NewReader("x"))
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+
+		_, err = blobs.Open(context.Background(), a.ID)
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		mine := testAttachment(t, attachments, task.ID, u.ID, "mine")
+
+		_, _, err = attachments.Open(context.Background(), mine.ID, other.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+
+		err = attachments.Delete(context.Background(), mine.ID, other.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+
+	t.Run("quota", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		blobs := testBlobs(t)
+		attachments := &models.AttachmentsModel{Pool: pool, Blobs: blobs}
+
+		_, err := pool.Exec(context.Background(), `INSERT INTO attachments (id, task_id, user_id, name, content_type, size)
+		VALUES ($1, $2, $3, 'big.bin', 'application/octet-stream', $4)`, db.NewID(), task.ID, u.ID, models.AttachmentQuota)
+		require.NoError(t, err)
+
+		a := &models.Attachment{ID: db.NewID(), TaskID: task.ID, UserID: u.ID, Name: "x.txt", ContentType: "text/plain"}
+
+		err = attachments.Create(context.Background(), a, strings.NewReader("x"))
+		require.ErrorIs(t, err, models.ErrQuotaExceeded)
+
+		_, err = blobs.Open(context.Background(), a.ID)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("removed with task", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		blobs := testBlobs(t)
+
+		tasks := &models.TasksModel{Pool: pool, Blobs: blobs}
+		task := testSubtask(t, tasks, u.ID, nil)
+		sub := testSubtask(t, tasks, u.ID, &task.ID)
+
+		attachments := &models.AttachmentsModel{Pool: pool, Blobs: blobs}
+		a := testAttachment(t, attachments, sub.ID, u.ID, "log")
+
+		require.NoError(t, tasks.Delete(context.Background(), task.ID, u.ID, 0))
+
+		// trashed tasks keep their attachments so they can be restored
+		rc, err := blobs.Open(context.Background(), a.ID)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+
+		_, err = tasks.EmptyTrash(context.Background(), u.ID)
+		require.NoError(t, err)
+
+		_, err = blobs.Open(context.Background(), a.ID)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
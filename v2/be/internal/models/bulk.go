@@ -0,0 +1,141 @@
+package models
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrTaskCompleted = errors.New("task is already completed")
+
+// Operations accepted by Bulk
+const (
+	BulkComplete = "complete"
+	BulkDelete   = "delete"
+	BulkTag      = "tag"
+	BulkMove     = "move"
+	BulkUpdate   = "update"
+)
+
+// BulkOps lists the operations accepted by Bulk
+var BulkOps = []string{BulkComplete, BulkDelete, BulkTag, BulkMove, BulkUpdate}
+
+// MaxBulkOps is the largest number of operations a single Bulk call may run
+const MaxBulkOps = 100
+
+// BulkOp is one operation run by Bulk on a task of the user. Only the fields
+// read by Op need to be set: Cascade for complete, Tags for tag, TargetID and
+// After for move, and Task, holding the new values as for Update, for update.
+type BulkOp struct {
+	Op       string
+	TaskID   string
+	Cascade  bool
+	Tags     []string
+	TargetID string
+	After    bool
+	Task     *Task
+}
+
+// BulkResult is the outcome of a BulkOp, Err being nil when it was applied
+type BulkResult struct {
+	TaskID string
+	Op     string
+	Err    error
+}
+
+// Bulk runs ops in order on the user's tasks within a single transaction and
+// returns the result of each. Every operation runs in its own savepoint, so
+// one that fails is rolled back while the others are applied.
+func (m *TasksModel) Bulk(ctx context.Context, userID string, ops []BulkOp) ([]*BulkResult, error) {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	results := make([]*BulkResult, 0, len(ops))
+
+	for _, op := range ops {
+		sp, serr := tx.Begin(ctx)
+		if serr != nil {
+			return nil, serr
+		}
+
+		oerr := runBulkOp(ctx, sp, userID, op)
+		if oerr != nil {
+			serr = sp.Rollback(ctx)
+		} else {
+			serr = sp.Commit(ctx)
+		}
+		if serr != nil {
+			return nil, serr
+		}
+
+		results = append(results, &BulkResult{TaskID: op.TaskID, Op: op.Op, Err: oerr})
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// runBulkOp applies a single operation of Bulk.
+func runBulkOp(ctx context.Context, tx pgx.Tx, userID string, op BulkOp) error {
+	var completed bool
+
+	err := tx.QueryRow(ctx, `SELECT completed FROM tasks
+	WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`, op.TaskID, userID).Scan(&completed)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	switch op.Op {
+	case BulkComplete:
+		if completed {
+			return ErrTaskCompleted
+		}
+
+		return completeTask(ctx, tx, op.TaskID, userID, op.Cascade)
+	case BulkDelete:
+		return deleteTask(ctx, tx, op.TaskID, userID)
+	case BulkTag:
+		before, serr := snapshotTask(ctx, tx, op.TaskID)
+		if serr != nil {
+			return serr
+		}
+
+		err = addTaskTags(ctx, tx, op.TaskID, userID, op.Tags)
+		if err != nil {
+			return err
+		}
+
+		return recordTaskEvent(ctx, tx, op.TaskID, userID, TaskUpdated, before)
+	case BulkMove:
+		return moveTask(ctx, tx, op.TaskID, userID, op.TargetID, op.After)
+	case BulkUpdate:
+		if completed {
+			return ErrTaskCompleted
+		}
+
+		t := *op.Task
+		t.ID, t.UserID = op.TaskID, userID
+
+		return updateTask(ctx, tx, &t)
+	default:
+		return ErrOpFailed
+	}
+}
@@ -0,0 +1,103 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTasksBulk(t *testing.T) {
+	t.Run("per item results", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		u := testUser(t, users)
+		other := testUser(t, users)
+
+		tasks := &models.TasksModel{Pool: pool}
+		first := testSubtask(t, tasks, u.ID, nil)
+		second := testSubtask(t, tasks, u.ID, nil)
+		third := testSubtask(t, tasks, u.ID, nil)
+		theirs := testSubtask(t, tasks, other.ID, nil)
+
+		ops := []models.BulkOp{
+			{Op: models.BulkComplete, TaskID: first.ID},
+			{Op: models.BulkComplete, TaskID: first.ID},
+			{Op: models.BulkTag, TaskID: second.ID, Tags: []string{"triage"}},
+			{Op: models.BulkMove, TaskID: third.ID, TargetID: second.ID},
+			{Op: models.BulkUpdate, TaskID: third.ID, Task: &models.Task{Title: second.Title, Description: "Clash"}},
+			{Op: models.BulkDelete, TaskID: theirs.ID},
+			{Op: models.BulkMove, TaskID: second.ID, TargetID: db.NewID(), After: true},
+			{Op: models.BulkUpdate, TaskID: second.ID, Task: &models.Task{Title: "Renamed", Description: "Triaged"}},
+		}
+
+		results, err := tasks.Bulk(context.Background(), u.ID, ops)
+		require.NoError(t, err)
+		require.Len(t, results, len(ops))
+
+		require.NoError(t, results[0].Err)
+		require.ErrorIs(t, results[1].Err, models.ErrTaskCompleted)
+		require.NoError(t, results[2].Err)
+		require.NoError(t, results[3].Err)
+		require.ErrorIs(t, results[4].Err, models.ErrDuplicateTask)
+		require.ErrorIs(t, results[5].Err, models.ErrRecordNotFound)
+		require.ErrorIs(t, results[6].Err, models.ErrMoveTargetNotFound)
+		require.NoError(t, results[7].Err)
+
+		rt, err := tasks.GetByID(context.Background(), first.ID, u.ID)
+		require.NoError(t, err)
+		require.True(t, rt.Completed)
+
+		rt, err = tasks.GetByID(context.Background(), second.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Renamed", rt.Title)
+		require.Equal(t, []string{"triage"}, rt.Tags)
+
+		rt, err = tasks.GetByID(context.Background(), third.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, third.Title, rt.Title)
+		require.Less(t, rt.Position, second.Position)
+
+		_, err = tasks.GetByID(context.Background(), theirs.ID, other.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("delete and tag", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		parent := testSubtask(t, tasks, u.ID, nil)
+		child := testSubtask(t, tasks, u.ID, &parent.ID)
+
+		results, err := tasks.Bulk(context.Background(), u.ID, []models.BulkOp{
+			{Op: models.BulkTag, TaskID: child.ID, Tags: []string{"a", "b"}},
+			{Op: models.BulkTag, TaskID: child.ID, Tags: []string{"b", "c"}},
+			{Op: models.BulkDelete, TaskID: parent.ID},
+			{Op: models.BulkComplete, TaskID: child.ID},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, results[0].Err)
+		require.NoError(t, results[1].Err)
+		require.NoError(t, results[2].Err)
+		require.ErrorIs(t, results[3].Err, models.ErrRecordNotFound)
+
+		trashed, err := tasks.Trash(context.Background(), u.ID)
+		require.NoError(t, err)
+		require.Len(t, trashed, 1)
+
+		require.NoError(t, tasks.Restore(context.Background(), parent.ID, u.ID))
+
+		rt, err := tasks.GetByID(context.Background(), child.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "c"}, rt.Tags)
+	})
+}
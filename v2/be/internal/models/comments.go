@@ -0,0 +1,251 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Comment struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"task_id"`
+	UserID    string    `json:"user_id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// commentColumns lists the columns read by scanComment, in scan order
+const commentColumns = `comments.id, comments.task_id, comments.user_id, users.username,
+	comments.body, comments.created_at, comments.updated_at`
+
+func scanComment(row pgx.Row) (*Comment, error) {
+	var c Comment
+
+	err := row.Scan(
+		&c.ID,
+		&c.TaskID,
+		&c.UserID,
+		&c.Author,
+		&c.Body,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+type CommentsModel struct {
+	Pool *pgxpool.Pool
+}
+
+// Create adds c to the thread of its task, which the author must be able to edit
+func (m *CommentsModel) Create(ctx context.Context, c *Comment) error {
+	query := `INSERT INTO comments (id, task_id, user_id, body)
+	VALUES ($1, $2, $3, $4)
+	RETURNING created_at, updated_at`
+
+	args := []any{c.ID, c.TaskID, c.UserID, c.Body}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = authorizeTask(ctx, tx, c.TaskID, c.UserID, RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, query, args...).Scan(&c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// All returns the comments of a task visible to the user, oldest first
+func (m *CommentsModel) All(ctx context.Context, taskID, userID string) ([]*Comment, error) {
+	query := `SELECT ` + commentColumns + `
+	FROM comments
+		JOIN users ON users.id = comments.user_id
+	WHERE comments.task_id = $1
+	ORDER BY comments.id`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = authorizeTask(ctx, tx, taskID, userID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []*Comment
+
+	rows, err := tx.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		c, cerr := scanComment(rows)
+		if cerr != nil {
+			return nil, cerr
+		}
+
+		comments = append(comments, c)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// GetByID returns a comment on a task visible to the user, whoever wrote it
+func (m *CommentsModel) GetByID(ctx context.Context, id, userID string) (*Comment, error) {
+	query := `SELECT ` + commentColumns + `
+	FROM comments
+		JOIN users ON users.id = comments.user_id
+	WHERE comments.id = $1`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	c, err := scanComment(tx.QueryRow(ctx, query, id))
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	_, err = authorizeTask(ctx, tx, c.TaskID, userID, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Update saves the body of a comment. Only its author may change it.
+func (m *CommentsModel) Update(ctx context.Context, c *Comment) error {
+	query := `UPDATE comments
+	SET body = $1, updated_at = now()
+	WHERE id = $2 AND user_id = $3
+	RETURNING updated_at`
+
+	args := []any{c.Body, c.ID, c.UserID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, query, args...).Scan(&c.UpdatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrOpFailed
+		default:
+			return err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes a comment. Only its author may delete it.
+func (m *CommentsModel) Delete(ctx context.Context, id, userID string) error {
+	query := `DELETE FROM comments
+	WHERE id = $1 AND user_id = $2`
+
+	args := []any{id, userID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrOpFailed
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComments(t *testing.T) {
+	t.Run("thread", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		comments := &models.CommentsModel{Pool: pool}
+
+		first := &models.Comment{ID: db.NewID(), TaskID: task.ID, UserID: u.ID, Body: "first"}
+		require.NoError(t, comments.Create(context.Background(), first))
+		require.False(t, first.CreatedAt.IsZero())
+
+		second := &models.Comment{ID: db.NewID(), TaskID: task.ID, UserID: u.ID, Body: "second"}
+		require.NoError(t, comments.Create(context.Background(), second))
+
+		all, err := comments.All(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		require.Equal(t, "first", all[0].Body)
+		require.Equal(t, u.Username, all[0].Author)
+
+		first.Body = "edited"
+		require.NoError(t, comments.Update(context.Background(), first))
+
+		c, err := comments.GetByID(context.Background(), first.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "edited", c.Body)
+		require.False(t, c.UpdatedAt.Before(c.CreatedAt))
+
+		require.NoError(t, comments.Delete(context.Background(), second.ID, u.ID))
+
+		_, err = comments.GetByID(context.Background(), second.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		u := testUser(t, users)
+		other := testUser(t, users)
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		comments := &models.CommentsModel{Pool: pool}
+
+		err := comments.Create(context.Background(), &models.Comment{ID: db.NewID(), TaskID: task.ID, UserID: other.ID, Body: "hi"})
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+
+		c := &models.Comment{ID: db.NewID(), TaskID: task.ID, UserID: u.ID, Body: "mine"}
+		require.NoError(t, comments.Create(context.Background(), c))
+
+		_, err = comments.GetByID(context.Background(), c.ID, other.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+
+		err = comments.Update(context.Background(), &models.Comment{ID: c.ID, UserID: other.ID, Body: "theirs"})
+		require.ErrorIs(t, err, models.ErrOpFailed)
+
+		err = comments.Delete(context.Background(), c.ID, other.ID)
+		require.ErrorIs(t, err, models.ErrOpFailed)
+	})
+
+	t.Run("shared task", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		u := testUser(t, users)
+		viewer := testUser(t, users)
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		shares := &models.SharesModel{Pool: pool}
+		s := &models.Share{ID: db.NewID(), OwnerID: u.ID, Username: viewer.Username, TaskID: &task.ID, Role: models.RoleViewer}
+		require.NoError(t, shares.Create(context.Background(), s))
+
+		comments := &models.CommentsModel{Pool: pool}
+
+		c := &models.Comment{ID: db.NewID(), TaskID: task.ID, UserID: u.ID, Body: "have a look"}
+		require.NoError(t, comments.Create(context.Background(), c))
+
+		all, err := comments.All(context.Background(), task.ID, viewer.ID)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+
+		_, err = comments.GetByID(context.Background(), c.ID, viewer.ID)
+		require.NoError(t, err)
+
+		err = comments.Create(context.Background(), &models.Comment{ID: db.NewID(), TaskID: task.ID, UserID: viewer.ID, Body: "looks good"})
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+
+	t.Run("deleted with task", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		comments := &models.CommentsModel{Pool: pool}
+
+		c := &models.Comment{ID: db.NewID(), TaskID: task.ID, UserID: u.ID, Body: "gone soon"}
+		require.NoError(t, comments.Create(context.Background(), c))
+
+		require.NoError(t, tasks.Delete(context.Background(), task.ID, u.ID, 0))
+
+		_, err := comments.GetByID(context.Background(), c.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the last row of a page so the next page can resume after it.
+// Sort is the order the page was listed in, Key holds the value of the sort
+// column and ID breaks ties between equal keys.
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k,omitempty"`
+	ID   string `json:"id"`
+}
+
+// Encode returns an opaque, URL safe representation of the cursor
+func (c *Cursor) Encode() string {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor previously produced by Encode for a list in
+// the given sort. A cursor made under another sort would skip or repeat rows,
+// so it is rejected like a malformed one.
+func DecodeCursor(s, sort string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	err = json.Unmarshal(b, &c)
+	if err != nil || c.ID == "" || c.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+
+	if strings.TrimPrefix(sort, "-") == "priority" && !slices.Contains(TaskPriorities, c.Key) {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
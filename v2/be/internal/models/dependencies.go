@@ -0,0 +1,137 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"v2/be/internal/db"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrDuplicateDependency = errors.New("task is already blocked by this task")
+	ErrDependencyCycle     = errors.New("dependency would create a cycle")
+	ErrOpenBlockers        = errors.New("task is blocked by open tasks")
+)
+
+// AddBlocker records that a task cannot be completed before blockerID is.
+// Both tasks must belong to the user and the new edge must not close a cycle.
+func (m *TasksModel) AddBlocker(ctx context.Context, id, blockerID, userID string) error {
+	query := `INSERT INTO task_dependencies (task_id, blocker_id)
+	VALUES ($1, $2)`
+
+	args := []any{id, blockerID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var owned int
+
+	err = tx.QueryRow(ctx, `SELECT count(*) FROM tasks
+	WHERE id IN ($1, $2) AND user_id = $3 AND deleted_at IS NULL`, id, blockerID, userID).Scan(&owned)
+	if err != nil {
+		return err
+	}
+
+	if owned != 2 {
+		return ErrRecordNotFound
+	}
+
+	// the edge closes a cycle when the task already blocks blockerID, directly or not
+	var cycle bool
+
+	err = tx.QueryRow(ctx, `WITH RECURSIVE blockers AS (
+		SELECT blocker_id FROM task_dependencies WHERE task_id = $1
+		UNION
+		SELECT d.blocker_id FROM task_dependencies d JOIN blockers b ON d.task_id = b.blocker_id
+	)
+	SELECT EXISTS (SELECT 1 FROM blockers WHERE blocker_id = $2)`, blockerID, id).Scan(&cycle)
+	if err != nil {
+		return err
+	}
+
+	if cycle {
+		return ErrDependencyCycle
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "task_dependencies_pkey"):
+			return ErrDuplicateDependency
+		default:
+			return err
+		}
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrOpFailed
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveBlocker deletes the edge saying the task is blocked by blockerID
+func (m *TasksModel) RemoveBlocker(ctx context.Context, id, blockerID, userID string) error {
+	query := `DELETE FROM task_dependencies
+	USING tasks
+	WHERE task_dependencies.task_id = $1 AND task_dependencies.blocker_id = $2
+		AND tasks.id = task_dependencies.task_id AND tasks.user_id = $3`
+
+	args := []any{id, blockerID, userID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrRecordNotFound
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// hasOpenBlockers reports whether a task is blocked by a task that is still open.
+func hasOpenBlockers(ctx context.Context, tx pgx.Tx, id string) (bool, error) {
+	var open bool
+
+	err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM task_dependencies d JOIN tasks b ON b.id = d.blocker_id
+	WHERE d.task_id = $1 AND b.completed = false AND b.deleted_at IS NULL)`, id).Scan(&open)
+	if err != nil {
+		return false, err
+	}
+
+	return open, nil
+}
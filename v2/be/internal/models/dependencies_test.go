@@ -0,0 +1,119 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTasksDependencies(t *testing.T) {
+	t.Run("blocks completion", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		release := testSubtask(t, tasks, u.ID, nil)
+		build := testSubtask(t, tasks, u.ID, nil)
+
+		require.NoError(t, tasks.AddBlocker(context.Background(), release.ID, build.ID, u.ID))
+
+		rt, err := tasks.GetByID(context.Background(), release.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{build.ID}, rt.BlockedBy)
+
+		bt, err := tasks.GetByID(context.Background(), build.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{release.ID}, bt.Blocking)
+
+		err = tasks.Complete(context.Background(), release.ID, u.ID, 0, false)
+		require.ErrorIs(t, err, models.ErrOpenBlockers)
+
+		require.NoError(t, tasks.Complete(context.Background(), build.ID, u.ID, 0, false))
+		require.NoError(t, tasks.Complete(context.Background(), release.ID, u.ID, 0, false))
+	})
+
+	t.Run("blocks cascading completion", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		release := testSubtask(t, tasks, u.ID, nil)
+		notes := testSubtask(t, tasks, u.ID, &release.ID)
+		review := testSubtask(t, tasks, u.ID, &release.ID)
+		build := testSubtask(t, tasks, u.ID, nil)
+
+		require.NoError(t, tasks.AddBlocker(context.Background(), notes.ID, build.ID, u.ID))
+		require.NoError(t, tasks.AddBlocker(context.Background(), notes.ID, review.ID, u.ID))
+
+		err := tasks.Complete(context.Background(), release.ID, u.ID, 0, true)
+		require.ErrorIs(t, err, models.ErrOpenBlockers)
+
+		nt, err := tasks.GetByID(context.Background(), notes.ID, u.ID)
+		require.NoError(t, err)
+		require.False(t, nt.Completed)
+
+		require.NoError(t, tasks.Complete(context.Background(), build.ID, u.ID, 0, false))
+		require.NoError(t, tasks.Complete(context.Background(), release.ID, u.ID, 0, true))
+	})
+
+	t.Run("rejects cycles", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		a := testSubtask(t, tasks, u.ID, nil)
+		b := testSubtask(t, tasks, u.ID, nil)
+		c := testSubtask(t, tasks, u.ID, nil)
+
+		require.NoError(t, tasks.AddBlocker(context.Background(), a.ID, b.ID, u.ID))
+		require.NoError(t, tasks.AddBlocker(context.Background(), b.ID, c.ID, u.ID))
+
+		err := tasks.AddBlocker(context.Background(), c.ID, a.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrDependencyCycle)
+
+		err = tasks.AddBlocker(context.Background(), a.ID, b.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrDuplicateDependency)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		u := testUser(t, users)
+		other := testUser(t, users)
+
+		tasks := &models.TasksModel{Pool: pool}
+		mine := testSubtask(t, tasks, u.ID, nil)
+		theirs := testSubtask(t, tasks, other.ID, nil)
+
+		err := tasks.AddBlocker(context.Background(), mine.ID, theirs.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		a := testSubtask(t, tasks, u.ID, nil)
+		b := testSubtask(t, tasks, u.ID, nil)
+
+		require.NoError(t, tasks.AddBlocker(context.Background(), a.ID, b.ID, u.ID))
+		require.NoError(t, tasks.RemoveBlocker(context.Background(), a.ID, b.ID, u.ID))
+
+		err := tasks.RemoveBlocker(context.Background(), a.ID, b.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,189 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"v2/be/internal/db"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Actions recorded in the history of a task
+const (
+	TaskCreated   = "created"
+	TaskUpdated   = "updated"
+	TaskCompleted = "completed"
+	TaskDeleted   = "deleted"
+)
+
+// FieldChange holds the value of a task field before and after an event
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// TaskEvent is one entry of the history of a task
+type TaskEvent struct {
+	ID        string                 `json:"id"`
+	TaskID    string                 `json:"task_id"`
+	UserID    string                 `json:"user_id"`
+	Action    string                 `json:"action"`
+	Changes   map[string]FieldChange `json:"changes"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// taskSnapshot selects the fields of a task tracked by its history
+const taskSnapshot = `SELECT jsonb_build_object(
+		'project_id', project_id,
+		'assignee_id', assignee_id,
+		'title', title,
+		'description', description,
+		'status', status,
+		'completed', completed,
+		'completed_at', completed_at,
+		'deleted_at', deleted_at,
+		'archived_at', archived_at,
+		'due_at', due_at,
+		'remind_at', remind_at,
+		'recurrence', recurrence,
+		'priority', priority,
+		'tags', ARRAY(SELECT tags.name FROM task_tags JOIN tags ON tags.id = task_tags.tag_id
+			WHERE task_tags.task_id = tasks.id ORDER BY tags.name)
+	)
+	FROM tasks
+	WHERE id = $1`
+
+// History returns the events of a task visible to the user, oldest first
+func (m *TasksModel) History(ctx context.Context, id, userID string) ([]*TaskEvent, error) {
+	query := `SELECT task_events.id, task_events.task_id, task_events.user_id, task_events.action,
+		task_events.changes, task_events.created_at
+	FROM task_events
+		JOIN tasks ON tasks.id = task_events.task_id
+	WHERE task_events.task_id = $1 AND tasks.user_id = $2
+	ORDER BY task_events.id`
+
+	args := []any{id, userID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var events []*TaskEvent
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var e TaskEvent
+
+		serr := rows.Scan(&e.ID, &e.TaskID, &e.UserID, &e.Action, &e.Changes, &e.CreatedAt)
+		if serr != nil {
+			return nil, serr
+		}
+
+		events = append(events, &e)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// snapshotTask returns the tracked fields of a task, failing with ErrOpFailed
+// when it does not exist.
+func snapshotTask(ctx context.Context, tx pgx.Tx, id string) (map[string]any, error) {
+	var s map[string]any
+
+	err := tx.QueryRow(ctx, taskSnapshot, id).Scan(&s)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, ErrOpFailed
+		default:
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// diffSnapshots returns the fields whose value differs between two snapshots
+func diffSnapshots(before, after map[string]any) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+
+	for field, value := range after {
+		if !reflect.DeepEqual(before[field], value) {
+			changes[field] = FieldChange{Old: before[field], New: value}
+		}
+	}
+
+	return changes
+}
+
+// recordTaskEvent adds an event to the history of a task with the fields that
+// changed since before, a nil before meaning the task was just created. The
+// event is credited to the actor of ctx, or to userID when there is none.
+func recordTaskEvent(ctx context.Context, tx pgx.Tx, id, userID, action string, before map[string]any) error {
+	after, err := snapshotTask(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO task_events (id, task_id, user_id, action, changes)
+	VALUES ($1, $2, $3, $4, $5)`, db.NewID(), id, actor(ctx, userID), action, diffSnapshots(before, after))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// snapshotTasks returns the tracked fields of each task in ids, in the same
+// order.
+func snapshotTasks(ctx context.Context, tx pgx.Tx, ids []string) ([]map[string]any, error) {
+	snapshots := make([]map[string]any, 0, len(ids))
+
+	for _, id := range ids {
+		s, err := snapshotTask(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, s)
+	}
+
+	return snapshots, nil
+}
+
+// recordTaskEvents adds an event to the history of each task in ids, taken
+// against the snapshot at the same index of before.
+func recordTaskEvents(ctx context.Context, tx pgx.Tx, ids []string, userID, action string, before []map[string]any) error {
+	for i, id := range ids {
+		err := recordTaskEvent(ctx, tx, id, userID, action, before[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
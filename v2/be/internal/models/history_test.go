@@ -0,0 +1,133 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTasksHistory(t *testing.T) {
+	t.Run("records mutations", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+		oldTitle := task.Title
+
+		task.Title = "renamed"
+		require.NoError(t, tasks.Update(context.Background(), task))
+		require.NoError(t, tasks.Complete(context.Background(), task.ID, u.ID, 0, false))
+		require.NoError(t, tasks.Delete(context.Background(), task.ID, u.ID, 0))
+
+		events, err := tasks.History(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.Len(t, events, 4)
+
+		require.Equal(t, models.TaskCreated, events[0].Action)
+		require.Equal(t, u.ID, events[0].UserID)
+		require.Equal(t, oldTitle, events[0].Changes["title"].New)
+		require.Nil(t, events[0].Changes["title"].Old)
+
+		require.Equal(t, models.TaskUpdated, events[1].Action)
+		require.Equal(t, models.FieldChange{Old: oldTitle, New: "renamed"}, events[1].Changes["title"])
+		require.NotContains(t, events[1].Changes, "description")
+
+		require.Equal(t, models.TaskCompleted, events[2].Action)
+		require.Equal(t, models.FieldChange{Old: false, New: true}, events[2].Changes["completed"])
+
+		require.Equal(t, models.TaskDeleted, events[3].Action)
+		require.Nil(t, events[3].Changes["deleted_at"].Old)
+		require.NotNil(t, events[3].Changes["deleted_at"].New)
+	})
+
+	t.Run("records state changes", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		parent := testSubtask(t, tasks, u.ID, nil)
+		child := testSubtask(t, tasks, u.ID, &parent.ID)
+
+		require.NoError(t, tasks.SetStatus(context.Background(), child.ID, u.ID, "in_progress"))
+		require.NoError(t, tasks.Complete(context.Background(), parent.ID, u.ID, 0, true))
+		require.NoError(t, tasks.Reopen(context.Background(), child.ID, u.ID))
+		require.NoError(t, tasks.Delete(context.Background(), parent.ID, u.ID, 0))
+		require.NoError(t, tasks.Restore(context.Background(), parent.ID, u.ID))
+
+		events, err := tasks.History(context.Background(), child.ID, u.ID)
+		require.NoError(t, err)
+		require.Len(t, events, 6)
+
+		require.Equal(t, models.TaskUpdated, events[1].Action)
+		require.Equal(t, models.FieldChange{Old: "todo", New: "in_progress"}, events[1].Changes["status"])
+
+		require.Equal(t, models.TaskCompleted, events[2].Action)
+		require.Equal(t, models.FieldChange{Old: false, New: true}, events[2].Changes["completed"])
+
+		require.Equal(t, models.TaskUpdated, events[3].Action)
+		require.Equal(t, models.FieldChange{Old: true, New: false}, events[3].Changes["completed"])
+
+		require.Equal(t, models.TaskDeleted, events[4].Action)
+		require.Nil(t, events[4].Changes["deleted_at"].Old)
+		require.NotNil(t, events[4].Changes["deleted_at"].New)
+
+		require.Equal(t, models.TaskUpdated, events[5].Action)
+		require.NotNil(t, events[5].Changes["deleted_at"].Old)
+		require.Nil(t, events[5].Changes["deleted_at"].New)
+
+		events, err = tasks.History(context.Background(), parent.ID, u.ID)
+		require.NoError(t, err)
+		require.Len(t, events, 5)
+
+		require.Equal(t, models.TaskCompleted, events[1].Action)
+		require.Equal(t, models.TaskUpdated, events[2].Action)
+		require.Equal(t, models.FieldChange{Old: true, New: false}, events[2].Changes["completed"])
+		require.Equal(t, models.TaskDeleted, events[3].Action)
+		require.Equal(t, models.TaskUpdated, events[4].Action)
+		require.NotNil(t, events[4].Changes["deleted_at"].Old)
+		require.Nil(t, events[4].Changes["deleted_at"].New)
+	})
+
+	t.Run("failed mutation is not recorded", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		parent := testSubtask(t, tasks, u.ID, nil)
+		testSubtask(t, tasks, u.ID, &parent.ID)
+
+		err := tasks.Complete(context.Background(), parent.ID, u.ID, 0, false)
+		require.ErrorIs(t, err, models.ErrOpenSubtasks)
+
+		events, err := tasks.History(context.Background(), parent.ID, u.ID)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, models.TaskCreated, events[0].Action)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		u := testUser(t, users)
+		other := testUser(t, users)
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		events, err := tasks.History(context.Background(), task.ID, other.ID)
+		require.NoError(t, err)
+		require.Empty(t, events)
+	})
+}
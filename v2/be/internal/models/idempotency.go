@@ -0,0 +1,191 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrIdempotencyKeyReused   = errors.New("idempotency key was already used for a different request")
+	ErrIdempotencyKeyInFlight = errors.New("a request with this idempotency key is still being processed")
+)
+
+const (
+	// DefaultIdempotencyTTL is how long the response to an idempotency key is kept
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	// idempotencyLockTimeout is how long a key stays reserved without a
+	// response before a retry may take it over, as when the server stopped
+	// while handling the first request
+	idempotencyLockTimeout = time.Minute
+)
+
+// StoredResponse is the response sent to the first request with an idempotency key
+type StoredResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+type IdempotencyModel struct {
+	Pool *pgxpool.Pool
+	TTL  time.Duration
+}
+
+// Reserve claims key for userID until the TTL of the model passes. It returns nil when the
+// caller should handle the request and then Save or Release the key, and the
+// stored response when the key was already used for the same fingerprint.
+// Anonymous requests share the empty userID.
+func (m *IdempotencyModel) Reserve(ctx context.Context, userID, key string, fingerprint []byte) (*StoredResponse, error) {
+	query := `INSERT INTO idempotency_keys (user_id, key, fingerprint, expires_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (user_id, key) DO NOTHING`
+
+	// concurrent retries of a key are the case this guards against, and under
+	// serializable isolation the second insert fails instead of waiting for
+	// the first to commit and then doing nothing
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM idempotency_keys
+	WHERE user_id = $1 AND key = $2
+		AND (expires_at <= now() OR (status IS NULL AND created_at <= $3))`,
+		userID, key, time.Now().Add(-idempotencyLockTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(ctx, query, userID, key, fingerprint, time.Now().Add(m.TTL))
+	if err != nil {
+		return nil, err
+	}
+
+	if result.RowsAffected() == 1 {
+		return nil, tx.Commit(ctx)
+	}
+
+	var stored []byte
+	var status *int
+
+	res := &StoredResponse{}
+
+	err = tx.QueryRow(ctx, `SELECT fingerprint, status, content_type, body
+	FROM idempotency_keys
+	WHERE user_id = $1 AND key = $2`, userID, key).Scan(&stored, &status, &res.ContentType, &res.Body)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			// the request holding the key released it in the meantime
+			return nil, ErrIdempotencyKeyInFlight
+		default:
+			return nil, err
+		}
+	}
+
+	switch {
+	case !bytes.Equal(stored, fingerprint):
+		return nil, ErrIdempotencyKeyReused
+	case status == nil:
+		return nil, ErrIdempotencyKeyInFlight
+	}
+
+	res.Status = *status
+
+	return res, nil
+}
+
+// Save stores res as the response to key, which must be reserved by userID
+func (m *IdempotencyModel) Save(ctx context.Context, userID, key string, res *StoredResponse) error {
+	query := `UPDATE idempotency_keys
+	SET status = $3, content_type = $4, body = $5
+	WHERE user_id = $1 AND key = $2 AND status IS NULL`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, userID, key, res.Status, res.ContentType, res.Body)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrRecordNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Release frees a key reserved by userID that has no response, so the
+// request can be retried
+func (m *IdempotencyModel) Release(ctx context.Context, userID, key string) error {
+	query := `DELETE FROM idempotency_keys
+	WHERE user_id = $1 AND key = $2 AND status IS NULL`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, query, userID, key)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Purge removes every expired key and returns how many were removed
+func (m *IdempotencyModel) Purge(ctx context.Context) (int64, error) {
+	query := `DELETE FROM idempotency_keys
+	WHERE expires_at <= now()`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
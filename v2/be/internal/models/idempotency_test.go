@@ -0,0 +1,118 @@
+package models_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeys(t *testing.T) {
+	t.Run("reserve save and replay", func(t *testing.T) {
+		t.Parallel()
+
+		im := &models.IdempotencyModel{Pool: testPool(t), TTL: time.Hour}
+		userID, key := db.NewID(), db.NewID()
+
+		res, err := im.Reserve(context.Background(), userID, key, []byte("first"))
+		require.NoError(t, err)
+		require.Nil(t, res)
+
+		_, err = im.Reserve(context.Background(), userID, key, []byte("first"))
+		require.ErrorIs(t, err, models.ErrIdempotencyKeyInFlight)
+
+		saved := &models.StoredResponse{Status: 201, ContentType: "application/json", Body: []byte(`{"payload":"1"}`)}
+		require.NoError(t, im.Save(context.Background(), userID, key, saved))
+
+		res, err = im.Reserve(context.Background(), userID, key, []byte("first"))
+		require.NoError(t, err)
+		require.Equal(t, saved, res)
+
+		_, err = im.Reserve(context.Background(), userID, key, []byte("second"))
+		require.ErrorIs(t, err, models.ErrIdempotencyKeyReused)
+
+		res, err = im.Reserve(context.Background(), db.NewID(), key, []byte("second"))
+		require.NoError(t, err)
+		require.Nil(t, res)
+	})
+
+	t.Run("concurrent reservations", func(t *testing.T) {
+		t.Parallel()
+
+		im := &models.IdempotencyModel{Pool: testPool(t), TTL: time.Hour}
+		userID, key := db.NewID(), db.NewID()
+
+		errs := make(chan error, 2)
+		for range 2 {
+			go func() {
+				_, err := im.Reserve(context.Background(), userID, key, []byte("first"))
+				errs <- err
+			}()
+		}
+
+		var reserved, inFlight int
+		for range 2 {
+			err := <-errs
+			switch {
+			case err == nil:
+				reserved++
+			case errors.Is(err, models.ErrIdempotencyKeyInFlight):
+				inFlight++
+			default:
+				require.NoError(t, err)
+			}
+		}
+
+		require.Equal(t, 1, reserved)
+		require.Equal(t, 1, inFlight)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		t.Parallel()
+
+		im := &models.IdempotencyModel{Pool: testPool(t), TTL: time.Hour}
+		userID, key := db.NewID(), db.NewID()
+
+		_, err := im.Reserve(context.Background(), userID, key, []byte("first"))
+		require.NoError(t, err)
+
+		require.NoError(t, im.Release(context.Background(), userID, key))
+
+		res, err := im.Reserve(context.Background(), userID, key, []byte("second"))
+		require.NoError(t, err)
+		require.Nil(t, res)
+	})
+
+	t.Run("expired key", func(t *testing.T) {
+		t.Parallel()
+
+		im := &models.IdempotencyModel{Pool: testPool(t), TTL: -time.Second}
+		userID, key := db.NewID(), db.NewID()
+
+		_, err := im.Reserve(context.Background(), userID, key, []byte("first"))
+		require.NoError(t, err)
+		require.NoError(t, im.Save(context.Background(), userID, key, &models.StoredResponse{Status: 201}))
+
+		res, err := im.Reserve(context.Background(), userID, key, []byte("second"))
+		require.NoError(t, err)
+		require.Nil(t, res)
+
+		n, err := im.Purge(context.Background())
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, n, int64(1))
+	})
+
+	t.Run("save without reservation", func(t *testing.T) {
+		t.Parallel()
+
+		im := &models.IdempotencyModel{Pool: testPool(t), TTL: time.Hour}
+
+		err := im.Save(context.Background(), db.NewID(), db.NewID(), &models.StoredResponse{Status: 201})
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+}
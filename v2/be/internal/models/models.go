@@ -12,17 +12,61 @@ var (
 )
 
 type Models struct {
-	Users *UsersModel
-	Tasks *TasksModel
+	Users       *UsersModel
+	Tasks       *TasksModel
+	Tags        *TagsModel
+	Projects    *ProjectsModel
+	Workflows   *WorkflowsModel
+	Comments    *CommentsModel
+	Attachments *AttachmentsModel
+	Shares      *SharesModel
+	Access      *AccessModel
+	Workspaces  *WorkspacesModel
+	Templates   *TemplatesModel
+	Idempotency *IdempotencyModel
 }
 
-func New(pool *pgxpool.Pool) *Models {
+func New(pool *pgxpool.Pool, blobs BlobStore) *Models {
 	return &Models{
 		Users: &UsersModel{
 			Pool: pool,
 		},
 		Tasks: &TasksModel{
+			Pool:  pool,
+			Blobs: blobs,
+		},
+		Tags: &TagsModel{
+			Pool: pool,
+		},
+		Projects: &ProjectsModel{
+			Pool:  pool,
+			Blobs: blobs,
+		},
+		Workflows: &WorkflowsModel{
+			Pool: pool,
+		},
+		Comments: &CommentsModel{
+			Pool: pool,
+		},
+		Attachments: &AttachmentsModel{
+			Pool:  pool,
+			Blobs: blobs,
+		},
+		Shares: &SharesModel{
+			Pool: pool,
+		},
+		Access: &AccessModel{
+			Pool: pool,
+		},
+		Workspaces: &WorkspacesModel{
+			Pool: pool,
+		},
+		Templates: &TemplatesModel{
+			Pool: pool,
+		},
+		Idempotency: &IdempotencyModel{
 			Pool: pool,
+			TTL:  DefaultIdempotencyTTL,
 		},
 	}
 }
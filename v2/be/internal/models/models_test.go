@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"v2/be/internal/blob"
 	"v2/be/internal/db"
 	"v2/be/internal/models"
 
@@ -138,7 +139,10 @@ func TestNew(t *testing.T) {
 	d, err := db.New(dsn)
 	require.Nil(t, err)
 
-	m := models.New(d)
+	blobs, err := blob.NewFS(t.TempDir())
+	require.NoError(t, err)
+
+	m := models.New(d, blobs)
 
 	require.NotNil(t, m)
 	require.NotEmpty(t, m)
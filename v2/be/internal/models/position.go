@@ -0,0 +1,216 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrMoveTargetNotFound = errors.New("target task not found")
+
+// positionDigits are the digits of position keys in ascending byte order. Keys
+// compare as plain strings, which is why the position column uses the C collation.
+const positionDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// firstPosition is the key of the first task in an empty list, leaving as much room before it as after it
+const firstPosition = "V"
+
+// positionBetween returns a key that sorts strictly between a and b, where an
+// empty a or b leaves that side unbounded. Keys never end with the zero digit
+// so there is always room for another key below them.
+func positionBetween(a, b string) string {
+	switch {
+	case a == "" && b == "":
+		return firstPosition
+	case a == "":
+		return positionBefore(b)
+	case b == "":
+		return positionAfter(a)
+	default:
+		return positionMidpoint(a, b)
+	}
+}
+
+// positionAfter returns a short key greater than k. Appending grows keys by
+// one digit every len(positionDigits) steps rather than halving the gap each time.
+func positionAfter(k string) string {
+	if k == "" {
+		return firstPosition
+	}
+
+	d := strings.IndexByte(positionDigits, k[0])
+	if d < len(positionDigits)-1 {
+		return positionDigits[d+1 : d+2]
+	}
+
+	return k[:1] + positionAfter(k[1:])
+}
+
+// positionBefore returns a short key smaller than k
+func positionBefore(k string) string {
+	if k == "" {
+		return firstPosition
+	}
+
+	d := strings.IndexByte(positionDigits, k[0])
+	if d > 1 {
+		return positionDigits[d-1 : d]
+	}
+
+	return positionDigits[:1] + positionBefore(k[1:])
+}
+
+// positionMidpoint returns a key between a and b, a < b, with b empty meaning
+// no upper bound
+func positionMidpoint(a, b string) string {
+	if b != "" {
+		// keep the shared prefix, padding a with zeros
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+
+			return b[:n] + positionMidpoint(rest, b[n:])
+		}
+	}
+
+	lo := 0
+	if a != "" {
+		lo = strings.IndexByte(positionDigits, a[0])
+	}
+
+	hi := len(positionDigits)
+	if b != "" {
+		hi = strings.IndexByte(positionDigits, b[0])
+	}
+
+	if hi-lo > 1 {
+		mid := (lo + hi + 1) / 2
+		return positionDigits[mid : mid+1]
+	}
+
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+
+	return positionDigits[lo:lo+1] + positionMidpoint(rest, "")
+}
+
+func digitAt(k string, i int) byte {
+	if i < len(k) {
+		return k[i]
+	}
+
+	return positionDigits[0]
+}
+
+// lastPosition returns a position after every task of the user so new tasks
+// are appended to the manual order.
+func lastPosition(ctx context.Context, tx pgx.Tx, userID string) (string, error) {
+	var last *string
+
+	err := tx.QueryRow(ctx, `SELECT max(position) FROM tasks WHERE user_id = $1`, userID).Scan(&last)
+	if err != nil {
+		return "", err
+	}
+
+	if last == nil {
+		return firstPosition, nil
+	}
+
+	return positionAfter(*last), nil
+}
+
+// Move places a task right before or, when after is set, right after the
+// target task in the user's manual order. Only the moved task is rewritten.
+func (m *TasksModel) Move(ctx context.Context, id, userID, targetID string, after bool) error {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = moveTask(ctx, tx, id, userID, targetID, after)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// moveTask does the work of Move.
+func moveTask(ctx context.Context, tx pgx.Tx, id, userID, targetID string, after bool) error {
+	query := `UPDATE tasks
+	SET position = $1
+	WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL`
+
+	var target string
+
+	err := tx.QueryRow(ctx, `SELECT position FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`, targetID, userID).Scan(&target)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrMoveTargetNotFound
+		default:
+			return err
+		}
+	}
+
+	neighbour := `SELECT max(position) FROM tasks WHERE user_id = $1 AND id <> $2 AND position < $3`
+	if after {
+		neighbour = `SELECT min(position) FROM tasks WHERE user_id = $1 AND id <> $2 AND position > $3`
+	}
+
+	var next *string
+
+	err = tx.QueryRow(ctx, neighbour, userID, id, target).Scan(&next)
+	if err != nil {
+		return err
+	}
+
+	lo, hi := "", target
+	if next != nil {
+		lo = *next
+	}
+
+	if after {
+		lo, hi = target, ""
+		if next != nil {
+			hi = *next
+		}
+	}
+
+	result, err := tx.Exec(ctx, query, positionBetween(lo, hi), id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrOpFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+// manualOrder returns the ids of the user's tasks in manual order
+func manualOrder(t *testing.T, tasks *models.TasksModel, userID string) []string {
+	t.Helper()
+
+	listed, _, err := tasks.All(context.Background(), userID, models.TaskFilter{Sort: "position"})
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(listed))
+	for _, task := range listed {
+		ids = append(ids, task.ID)
+	}
+
+	return ids
+}
+
+func TestTasksMove(t *testing.T) {
+	t.Run("reorders", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		a := testSubtask(t, tasks, u.ID, nil)
+		b := testSubtask(t, tasks, u.ID, nil)
+		c := testSubtask(t, tasks, u.ID, nil)
+
+		require.Equal(t, []string{a.ID, b.ID, c.ID}, manualOrder(t, tasks, u.ID))
+
+		require.NoError(t, tasks.Move(context.Background(), c.ID, u.ID, a.ID, false))
+		require.Equal(t, []string{c.ID, a.ID, b.ID}, manualOrder(t, tasks, u.ID))
+
+		require.NoError(t, tasks.Move(context.Background(), c.ID, u.ID, a.ID, true))
+		require.Equal(t, []string{a.ID, c.ID, b.ID}, manualOrder(t, tasks, u.ID))
+
+		require.NoError(t, tasks.Move(context.Background(), a.ID, u.ID, b.ID, true))
+		require.Equal(t, []string{c.ID, b.ID, a.ID}, manualOrder(t, tasks, u.ID))
+
+		d := testSubtask(t, tasks, u.ID, nil)
+		require.Equal(t, []string{c.ID, b.ID, a.ID, d.ID}, manualOrder(t, tasks, u.ID))
+	})
+
+	t.Run("missing target", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		err := tasks.Move(context.Background(), task.ID, u.ID, db.NewID(), true)
+		require.ErrorIs(t, err, models.ErrMoveTargetNotFound)
+	})
+}
+
+func TestTasksPriority(t *testing.T) {
+	t.Parallel()
+
+	pool := testPool(t)
+	u := testUser(t, &models.UsersModel{Pool: pool})
+
+	tasks := &models.TasksModel{Pool: pool}
+
+	for _, p := range []string{"low", "urgent", ""} {
+		task := &models.Task{ID: db.NewID(), UserID: u.ID, Title: gofakeit.UUID(), Description: gofakeit.Phrase(), Priority: p}
+		require.NoError(t, tasks.Create(context.Background(), task))
+	}
+
+	listed, _, err := tasks.All(context.Background(), u.ID, models.TaskFilter{Sort: "-priority", Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, "urgent", listed[0].Priority)
+	require.Equal(t, "low", listed[1].Priority)
+
+	c, err := models.DecodeCursor((&models.Cursor{Sort: "-priority", Key: listed[1].Priority, ID: listed[1].ID}).Encode(), "-priority")
+	require.NoError(t, err)
+
+	rest, _, err := tasks.All(context.Background(), u.ID, models.TaskFilter{Sort: "-priority", Cursor: c})
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	require.Equal(t, "none", rest[0].Priority)
+}
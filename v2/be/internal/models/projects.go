@@ -0,0 +1,316 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"v2/be/internal/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// InboxName is the name given to the project every user starts with
+const InboxName = "Inbox"
+
+var (
+	ErrDuplicateProject = errors.New("project exists")
+	ErrProjectNotFound  = errors.New("project not found")
+)
+
+type Project struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+	Inbox  bool   `json:"inbox"`
+}
+
+type ProjectsModel struct {
+	Pool  *pgxpool.Pool
+	Blobs BlobStore
+}
+
+func (m *ProjectsModel) Create(ctx context.Context, p *Project) error {
+	query := `INSERT INTO projects (id, user_id, name)
+	VALUES ($1, $2, $3)`
+
+	args := []any{p.ID, p.UserID, p.Name}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "projects_name_user_id_key"):
+			return ErrDuplicateProject
+		default:
+			return err
+		}
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrOpFailed
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateInbox creates the user's inbox project if it does not exist yet and returns its id
+func (m *ProjectsModel) CreateInbox(ctx context.Context, userID string) (string, error) {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	defer tx.Rollback(ctx)
+
+	id, err := ensureInbox(ctx, tx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (m *ProjectsModel) All(ctx context.Context, userID string) ([]*Project, error) {
+	query := `SELECT id, user_id, name, inbox
+	FROM projects
+	WHERE user_id = $1
+	ORDER BY inbox DESC, name`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var projects []*Project
+
+	rows, err := tx.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var p Project
+		perr := rows.Scan(
+			&p.ID,
+			&p.UserID,
+			&p.Name,
+			&p.Inbox,
+		)
+
+		if perr != nil {
+			return nil, perr
+		}
+
+		projects = append(projects, &p)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (m *ProjectsModel) GetByID(ctx context.Context, id, userID string) (*Project, error) {
+	query := `SELECT id, user_id, name, inbox
+	FROM projects
+	WHERE id = $1 AND user_id = $2`
+
+	args := []any{id, userID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var p Project
+
+	err = tx.QueryRow(ctx, query, args...).Scan(
+		&p.ID,
+		&p.UserID,
+		&p.Name,
+		&p.Inbox,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (m *ProjectsModel) Update(ctx context.Context, p *Project) error {
+	query := `UPDATE projects
+	SET name = $1
+	WHERE id = $2 AND user_id = $3 AND inbox = false`
+
+	args := []any{p.Name, p.ID, p.UserID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "projects_name_user_id_key"):
+			return ErrDuplicateProject
+		default:
+			return err
+		}
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrOpFailed
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes a project together with its tasks and their attachments.
+// The inbox cannot be deleted.
+func (m *ProjectsModel) Delete(ctx context.Context, id, userID string) error {
+	query := `DELETE FROM projects
+	WHERE id = $1 AND user_id = $2 AND inbox = false`
+
+	args := []any{id, userID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	attachments, err := deleteAttachments(ctx, tx, `SELECT tasks.id FROM tasks
+		JOIN projects ON projects.id = tasks.project_id
+	WHERE projects.id = $1 AND projects.user_id = $2 AND projects.inbox = false`, id, userID)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrOpFailed
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return deleteBlobs(ctx, m.Blobs, attachments)
+}
+
+// ensureInbox creates the user's inbox when missing and returns its id.
+func ensureInbox(ctx context.Context, tx pgx.Tx, userID string) (string, error) {
+	_, err := tx.Exec(ctx, `INSERT INTO projects (id, user_id, name, inbox)
+	VALUES ($1, $2, $3, true)
+	ON CONFLICT DO NOTHING`, db.NewID(), userID, InboxName)
+	if err != nil {
+		return "", err
+	}
+
+	var id string
+
+	err = tx.QueryRow(ctx, `SELECT id FROM projects WHERE user_id = $1 AND inbox`, userID).Scan(&id)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return "", ErrProjectNotFound
+		default:
+			return "", err
+		}
+	}
+
+	return id, nil
+}
+
+// checkProject ensures the project exists and belongs to the user.
+func checkProject(ctx context.Context, tx pgx.Tx, id, userID string) error {
+	var exists bool
+
+	err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM projects WHERE id = $1 AND user_id = $2)`, id, userID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrProjectNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectsCreate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		projects := &models.ProjectsModel{Pool: pool}
+		p := &models.Project{ID: db.NewID(), UserID: u.ID, Name: "work"}
+
+		err := projects.Create(context.Background(), p)
+		require.NoError(t, err)
+
+		rp, err := projects.GetByID(context.Background(), p.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, p.Name, rp.Name)
+		require.False(t, rp.Inbox)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		projects := &models.ProjectsModel{Pool: pool}
+
+		err := projects.Create(context.Background(), &models.Project{ID: db.NewID(), UserID: u.ID, Name: "work"})
+		require.NoError(t, err)
+
+		err = projects.Create(context.Background(), &models.Project{ID: db.NewID(), UserID: u.ID, Name: "work"})
+		require.ErrorIs(t, err, models.ErrDuplicateProject)
+	})
+}
+
+func TestProjectsCreateInbox(t *testing.T) {
+	t.Parallel()
+
+	pool := testPool(t)
+	u := testUser(t, &models.UsersModel{Pool: pool})
+
+	projects := &models.ProjectsModel{Pool: pool}
+
+	id, err := projects.CreateInbox(context.Background(), u.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+
+	again, err := projects.CreateInbox(context.Background(), u.ID)
+	require.NoError(t, err)
+	require.Equal(t, id, again)
+
+	ps, err := projects.All(context.Background(), u.ID)
+	require.NoError(t, err)
+	require.Len(t, ps, 1)
+	require.True(t, ps[0].Inbox)
+	require.Equal(t, models.InboxName, ps[0].Name)
+}
+
+func TestProjectsUpdateAndDelete(t *testing.T) {
+	t.Parallel()
+
+	pool := testPool(t)
+	u := testUser(t, &models.UsersModel{Pool: pool})
+
+	projects := &models.ProjectsModel{Pool: pool}
+
+	inbox, err := projects.CreateInbox(context.Background(), u.ID)
+	require.NoError(t, err)
+
+	p := &models.Project{ID: db.NewID(), UserID: u.ID, Name: "work"}
+	require.NoError(t, projects.Create(context.Background(), p))
+
+	p.Name = "office"
+	err = projects.Update(context.Background(), p)
+	require.NoError(t, err)
+
+	err = projects.Update(context.Background(), &models.Project{ID: inbox, UserID: u.ID, Name: "renamed"})
+	require.ErrorIs(t, err, models.ErrOpFailed)
+
+	err = projects.Delete(context.Background(), inbox, u.ID)
+	require.ErrorIs(t, err, models.ErrOpFailed)
+
+	tasks := &models.TasksModel{Pool: pool}
+	task := &models.Task{
+		ID:          db.NewID(),
+		UserID:      u.ID,
+		ProjectID:   p.ID,
+		Title:       gofakeit.BookTitle(),
+		Description: gofakeit.Phrase(),
+	}
+	require.NoError(t, tasks.Create(context.Background(), task))
+
+	err = projects.Delete(context.Background(), p.ID, u.ID)
+	require.NoError(t, err)
+
+	_, err = tasks.GetByID(context.Background(), task.ID, u.ID)
+	require.ErrorIs(t, err, models.ErrRecordNotFound)
+}
+
+func TestTaskProjects(t *testing.T) {
+	t.Parallel()
+
+	pool := testPool(t)
+	users := &models.UsersModel{Pool: pool}
+	u := testUser(t, users)
+	other := testUser(t, users)
+
+	projects := &models.ProjectsModel{Pool: pool}
+	p := &models.Project{ID: db.NewID(), UserID: u.ID, Name: "work"}
+	require.NoError(t, projects.Create(context.Background(), p))
+
+	foreign := &models.Project{ID: db.NewID(), UserID: other.ID, Name: "work"}
+	require.NoError(t, projects.Create(context.Background(), foreign))
+
+	tasks := &models.TasksModel{Pool: pool}
+
+	inboxed := &models.Task{ID: db.NewID(), UserID: u.ID, Title: "plan", Description: gofakeit.Phrase()}
+	require.NoError(t, tasks.Create(context.Background(), inboxed))
+	require.NotEmpty(t, inboxed.ProjectID)
+
+	scoped := &models.Task{ID: db.NewID(), UserID: u.ID, ProjectID: p.ID, Title: "plan", Description: gofakeit.Phrase()}
+	require.NoError(t, tasks.Create(context.Background(), scoped))
+
+	dup := &models.Task{ID: db.NewID(), UserID: u.ID, ProjectID: p.ID, Title: "plan", Description: gofakeit.Phrase()}
+	require.ErrorIs(t, tasks.Create(context.Background(), dup), models.ErrDuplicateTask)
+
+	stolen := &models.Task{ID: db.NewID(), UserID: u.ID, ProjectID: foreign.ID, Title: "steal", Description: gofakeit.Phrase()}
+	require.ErrorIs(t, tasks.Create(context.Background(), stolen), models.ErrProjectNotFound)
+
+	ts, _, err := tasks.All(context.Background(), u.ID, models.TaskFilter{ProjectID: p.ID})
+	require.NoError(t, err)
+	require.Len(t, ts, 1)
+	require.Equal(t, scoped.ID, ts[0].ID)
+
+	inboxed.ProjectID = foreign.ID
+	err = tasks.Update(context.Background(), inboxed)
+	require.ErrorIs(t, err, models.ErrProjectNotFound)
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// queryArgs collects positional arguments for dynamically built queries
+type queryArgs []any
+
+// add appends v and returns its placeholder
+func (a *queryArgs) add(v any) string {
+	*a = append(*a, v)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns an ILIKE pattern matching s anywhere in a string
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
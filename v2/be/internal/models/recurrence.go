@@ -0,0 +1,168 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"v2/be/internal/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/teambition/rrule-go"
+)
+
+var ErrInvalidRecurrence = errors.New("must be a daily, weekly, monthly or yearly RRULE")
+
+// recurrenceFreqs lists the frequencies a task may repeat at, anything finer would flood the task list
+var recurrenceFreqs = []rrule.Frequency{rrule.DAILY, rrule.WEEKLY, rrule.MONTHLY, rrule.YEARLY}
+
+// ParseRecurrence validates an iCalendar RRULE such as FREQ=WEEKLY;BYDAY=MO and
+// returns it in canonical form. The series starts at the task due date so DTSTART is not accepted.
+func ParseRecurrence(s string) (string, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if strings.ContainsAny(s, "\n") || strings.Contains(s, "DTSTART") {
+		return "", ErrInvalidRecurrence
+	}
+
+	opt, err := rrule.StrToROption(s)
+	if err != nil {
+		return "", ErrInvalidRecurrence
+	}
+
+	if !slices.Contains(recurrenceFreqs, opt.Freq) || opt.Count < 0 || opt.Interval < 0 {
+		return "", ErrInvalidRecurrence
+	}
+
+	_, err = rrule.NewRRule(*opt)
+	if err != nil {
+		return "", ErrInvalidRecurrence
+	}
+
+	return opt.RRuleString(), nil
+}
+
+// nextOccurrence returns the due date of the occurrence following one due at
+// dueAt and the rule it carries on with. Missed occurrences before now are
+// skipped and a task without a due date repeats from now. ok is false once the
+// series has ended through COUNT or UNTIL.
+func nextOccurrence(rule string, dueAt *time.Time, now time.Time) (next time.Time, carry string, ok bool, err error) {
+	opt, err := rrule.StrToROption(rule)
+	if err != nil {
+		return time.Time{}, "", false, ErrInvalidRecurrence
+	}
+
+	// COUNT includes the occurrence being completed
+	if opt.Count == 1 {
+		return time.Time{}, "", false, nil
+	}
+
+	from := now
+	if dueAt != nil {
+		opt.Dtstart = *dueAt
+		if dueAt.After(now) {
+			from = *dueAt
+		}
+	} else {
+		opt.Dtstart = now
+	}
+
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return time.Time{}, "", false, ErrInvalidRecurrence
+	}
+
+	next = r.After(from, false)
+	if next.IsZero() {
+		return time.Time{}, "", false, nil
+	}
+
+	// the series carries on from next, so COUNT keeps only the occurrences
+	// left from there, missed ones included
+	if opt.Count > 0 {
+		remaining := 0
+		for _, o := range r.All() {
+			if !o.Before(next) {
+				remaining++
+			}
+		}
+
+		opt.Count = remaining
+	}
+
+	return next, opt.RRuleString(), true, nil
+}
+
+// createNextOccurrence adds the open task that follows the recurring task id,
+// copying its content and tags and shifting its dates. It does nothing when
+// the task does not recur or its series has ended.
+func createNextOccurrence(ctx context.Context, tx pgx.Tx, id, userID string) error {
+	var (
+		rule            *string
+		dueAt, remindAt *time.Time
+	)
+
+	err := tx.QueryRow(ctx, `SELECT recurrence, due_at, remind_at FROM tasks WHERE id = $1 AND user_id = $2`, id, userID).Scan(&rule, &dueAt, &remindAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	if rule == nil {
+		return nil
+	}
+
+	next, carry, ok, err := nextOccurrence(*rule, dueAt, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return nil
+	}
+
+	var nextRemind *time.Time
+	if remindAt != nil {
+		lead := time.Duration(0)
+		if dueAt != nil {
+			lead = dueAt.Sub(*remindAt)
+		}
+
+		r := next.Add(-lead)
+		nextRemind = &r
+	}
+
+	position, err := lastPosition(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	nextID := db.NewID()
+
+	_, err = tx.Exec(ctx, `INSERT INTO tasks (id, user_id, project_id, parent_id, assignee_id, workspace_id, title, description, due_at, remind_at, recurrence, priority, position, status)
+	SELECT $1::TEXT, user_id, project_id, parent_id, assignee_id, workspace_id, title, description, $3::TIMESTAMPTZ, $4::TIMESTAMPTZ, $5::TEXT, priority, $6::TEXT, `+initialStatus+`
+	FROM tasks
+	WHERE id = $2`, nextID, id, next, nextRemind, carry, position)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "tasks_title_project_id_key"):
+			return ErrDuplicateTask
+		default:
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO task_tags (task_id, tag_id)
+	SELECT $1, tag_id FROM task_tags
+	WHERE task_id = $2`, nextID, id)
+	if err != nil {
+		return err
+	}
+
+	return recordTaskEvent(ctx, tx, nextID, userID, TaskCreated, nil)
+}
@@ -0,0 +1,198 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		want string
+		err  error
+	}{
+		{name: "weekly", rule: "FREQ=WEEKLY;BYDAY=MO", want: "FREQ=WEEKLY;BYDAY=MO"},
+		{name: "prefixed", rule: " RRULE:FREQ=DAILY;INTERVAL=2 ", want: "FREQ=DAILY;INTERVAL=2"},
+		{name: "count", rule: "FREQ=MONTHLY;COUNT=3", want: "FREQ=MONTHLY;COUNT=3"},
+		{name: "unknown frequency", rule: "FREQ=FORTNIGHTLY", err: models.ErrInvalidRecurrence},
+		{name: "too frequent", rule: "FREQ=MINUTELY", err: models.ErrInvalidRecurrence},
+		{name: "missing frequency", rule: "BYDAY=MO", err: models.ErrInvalidRecurrence},
+		{name: "dtstart", rule: "FREQ=DAILY;DTSTART=20240801T090000Z", err: models.ErrInvalidRecurrence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := models.ParseRecurrence(tt.rule)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, rule)
+		})
+	}
+}
+
+func TestTasksCompleteRecurring(t *testing.T) {
+	t.Run("next occurrence", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+
+		due := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+		remind := due.Add(-time.Hour)
+		rule := "FREQ=WEEKLY"
+
+		task := &models.Task{
+			ID:          db.NewID(),
+			UserID:      u.ID,
+			Title:       "weekly report",
+			Description: "send it to the team",
+			DueAt:       &due,
+			RemindAt:    &remind,
+			Recurrence:  &rule,
+			Tags:        []string{"work"},
+		}
+
+		require.NoError(t, tasks.Create(context.Background(), task))
+		require.NoError(t, tasks.Complete(context.Background(), task.ID, u.ID, 0, false))
+
+		open := false
+		next, _, err := tasks.All(context.Background(), u.ID, models.TaskFilter{Completed: &open})
+		require.NoError(t, err)
+		require.Len(t, next, 1)
+
+		n := next[0]
+		require.NotEqual(t, task.ID, n.ID)
+		require.Equal(t, task.Title, n.Title)
+		require.Equal(t, task.ProjectID, n.ProjectID)
+		require.Equal(t, []string{"work"}, n.Tags)
+		require.Equal(t, rule, *n.Recurrence)
+		require.True(t, due.AddDate(0, 0, 7).Equal(*n.DueAt))
+		require.True(t, remind.AddDate(0, 0, 7).Equal(*n.RemindAt))
+	})
+
+	t.Run("count", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+
+		due := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+		rule := "FREQ=DAILY;COUNT=3"
+
+		task := &models.Task{
+			ID:          db.NewID(),
+			UserID:      u.ID,
+			Title:       "take the pills",
+			Description: "one after breakfast",
+			DueAt:       &due,
+			Recurrence:  &rule,
+		}
+
+		require.NoError(t, tasks.Create(context.Background(), task))
+
+		open := false
+		var dues []time.Time
+
+		for id := task.ID; id != ""; {
+			require.NoError(t, tasks.Complete(context.Background(), id, u.ID, 0, false))
+
+			next, _, err := tasks.All(context.Background(), u.ID, models.TaskFilter{Completed: &open})
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(next), 1)
+
+			id = ""
+			if len(next) == 1 {
+				id = next[0].ID
+				dues = append(dues, *next[0].DueAt)
+			}
+		}
+
+		require.Len(t, dues, 2)
+		require.True(t, due.AddDate(0, 0, 1).Equal(dues[0]))
+		require.True(t, due.AddDate(0, 0, 2).Equal(dues[1]))
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		root := testSubtask(t, tasks, u.ID, nil)
+
+		due := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+		rule := "FREQ=DAILY"
+
+		child := &models.Task{
+			ID:          db.NewID(),
+			UserID:      u.ID,
+			ParentID:    &root.ID,
+			Title:       "water the plants",
+			Description: "the ones on the balcony",
+			DueAt:       &due,
+			Recurrence:  &rule,
+		}
+
+		require.NoError(t, tasks.Create(context.Background(), child))
+		require.NoError(t, tasks.Complete(context.Background(), root.ID, u.ID, 0, true))
+
+		children, err := tasks.Children(context.Background(), root.ID, u.ID)
+		require.NoError(t, err)
+		require.Len(t, children, 2)
+
+		var next *models.Task
+		for _, c := range children {
+			if c.ID != child.ID {
+				next = c
+			}
+		}
+
+		require.NotNil(t, next)
+		require.False(t, next.Completed)
+		require.Equal(t, child.Title, next.Title)
+		require.True(t, due.AddDate(0, 0, 1).Equal(*next.DueAt))
+	})
+
+	t.Run("series ended", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+
+		rule := "FREQ=DAILY;COUNT=1"
+
+		task := &models.Task{
+			ID:          db.NewID(),
+			UserID:      u.ID,
+			Title:       "rotate on-call",
+			Description: "hand over the pager",
+			Recurrence:  &rule,
+		}
+
+		require.NoError(t, tasks.Create(context.Background(), task))
+		require.NoError(t, tasks.Complete(context.Background(), task.ID, u.ID, 0, false))
+
+		open := false
+		next, _, err := tasks.All(context.Background(), u.ID, models.TaskFilter{Completed: &open})
+		require.NoError(t, err)
+		require.Empty(t, next)
+	})
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"v2/be/internal/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrShareUserNotFound = errors.New("user not found")
+	ErrShareWithSelf     = errors.New("cannot share with yourself")
+	ErrDuplicateShare    = errors.New("already shared with this user")
+)
+
+// Share grants a user access to a task and its subtasks, or to every task of a project
+type Share struct {
+	ID        string    `json:"id"`
+	OwnerID   string    `json:"owner_id"`
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	TaskID    *string   `json:"task_id"`
+	ProjectID *string   `json:"project_id"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SharesModel struct {
+	Pool *pgxpool.Pool
+}
+
+// Create grants s.Username access to a task or project owned by s.OwnerID
+func (m *SharesModel) Create(ctx context.Context, s *Share) error {
+	query := `INSERT INTO shares (id, owner_id, user_id, task_id, project_id, role)
+	SELECT $1::TEXT, $2::TEXT, $3::TEXT, $4::TEXT, $5::TEXT, $6::share_role
+	WHERE EXISTS (SELECT 1 FROM tasks WHERE id = $4 AND user_id = $2 AND deleted_at IS NULL)
+		OR EXISTS (SELECT 1 FROM projects WHERE id = $5 AND user_id = $2)
+	RETURNING created_at`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `SELECT id FROM users WHERE username = $1`, s.Username).Scan(&s.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrShareUserNotFound
+		default:
+			return err
+		}
+	}
+
+	if s.UserID == s.OwnerID {
+		return ErrShareWithSelf
+	}
+
+	args := []any{s.ID, s.OwnerID, s.UserID, s.TaskID, s.ProjectID, s.Role}
+
+	err = tx.QueryRow(ctx, query, args...).Scan(&s.CreatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrRecordNotFound
+		case strings.Contains(db.FormatErr(err), "shares_task_id_user_id_key"),
+			strings.Contains(db.FormatErr(err), "shares_project_id_user_id_key"):
+			return ErrDuplicateShare
+		default:
+			return err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// All returns the shares the user granted or received, oldest first
+func (m *SharesModel) All(ctx context.Context, userID string) ([]*Share, error) {
+	query := `SELECT shares.id, shares.owner_id, shares.user_id, users.username,
+		shares.task_id, shares.project_id, shares.role::TEXT, shares.created_at
+	FROM shares
+		JOIN users ON users.id = shares.user_id
+	WHERE shares.owner_id = $1 OR shares.user_id = $1
+	ORDER BY shares.id`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var shares []*Share
+
+	rows, err := tx.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var s Share
+
+		serr := rows.Scan(&s.ID, &s.OwnerID, &s.UserID, &s.Username, &s.TaskID, &s.ProjectID, &s.Role, &s.CreatedAt)
+		if serr != nil {
+			return nil, serr
+		}
+
+		shares = append(shares, &s)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+// Delete removes a share. The owner can revoke it and the recipient can leave it.
+func (m *SharesModel) Delete(ctx context.Context, id, userID string) error {
+	query := `DELETE FROM shares
+	WHERE id = $1 AND (owner_id = $2 OR user_id = $2)`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrRecordNotFound
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShares(t *testing.T) {
+	t.Run("create and revoke", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		owner := testUser(t, users)
+		other := testUser(t, users)
+
+		task := testSubtask(t, &models.TasksModel{Pool: pool}, owner.ID, nil)
+
+		shares := &models.SharesModel{Pool: pool}
+
+		s := &models.Share{ID: db.NewID(), OwnerID: owner.ID, Username: other.Username, TaskID: &task.ID, Role: models.RoleViewer}
+		require.NoError(t, shares.Create(context.Background(), s))
+		require.Equal(t, other.ID, s.UserID)
+		require.False(t, s.CreatedAt.IsZero())
+
+		dup := &models.Share{ID: db.NewID(), OwnerID: owner.ID, Username: other.Username, TaskID: &task.ID, Role: models.RoleEditor}
+		require.ErrorIs(t, shares.Create(context.Background(), dup), models.ErrDuplicateShare)
+
+		granted, err := shares.All(context.Background(), owner.ID)
+		require.NoError(t, err)
+		require.Len(t, granted, 1)
+
+		received, err := shares.All(context.Background(), other.ID)
+		require.NoError(t, err)
+		require.Len(t, received, 1)
+		require.Equal(t, other.Username, received[0].Username)
+		require.Equal(t, models.RoleViewer, received[0].Role)
+
+		require.NoError(t, shares.Delete(context.Background(), s.ID, other.ID))
+		require.ErrorIs(t, shares.Delete(context.Background(), s.ID, owner.ID), models.ErrRecordNotFound)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		owner := testUser(t, users)
+		other := testUser(t, users)
+
+		task := testSubtask(t, &models.TasksModel{Pool: pool}, owner.ID, nil)
+
+		shares := &models.SharesModel{Pool: pool}
+
+		s := &models.Share{ID: db.NewID(), OwnerID: owner.ID, Username: "nobody-" + db.NewID(), TaskID: &task.ID, Role: models.RoleViewer}
+		require.ErrorIs(t, shares.Create(context.Background(), s), models.ErrShareUserNotFound)
+
+		s = &models.Share{ID: db.NewID(), OwnerID: owner.ID, Username: owner.Username, TaskID: &task.ID, Role: models.RoleViewer}
+		require.ErrorIs(t, shares.Create(context.Background(), s), models.ErrShareWithSelf)
+
+		s = &models.Share{ID: db.NewID(), OwnerID: other.ID, Username: owner.Username, TaskID: &task.ID, Role: models.RoleViewer}
+		require.ErrorIs(t, shares.Create(context.Background(), s), models.ErrRecordNotFound)
+	})
+}
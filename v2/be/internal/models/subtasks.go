@@ -0,0 +1,272 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"v2/be/internal/db"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MaxTaskDepth is the number of levels a task tree may have, counting the root task
+const MaxTaskDepth = 3
+
+var (
+	ErrParentNotFound = errors.New("parent task not found")
+	ErrTaskTooDeep    = errors.New("subtasks cannot be nested this deep")
+	ErrOpenSubtasks   = errors.New("task has open subtasks")
+)
+
+// TaskProgress counts the direct subtasks of a task and how many of them are completed
+type TaskProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// Children returns the direct subtasks of a task in creation order
+func (m *TasksModel) Children(ctx context.Context, id, userID string) ([]*Task, error) {
+	query := `SELECT ` + taskColumns + `
+	FROM tasks
+	WHERE parent_id = $1 AND user_id = $2 AND deleted_at IS NULL
+	ORDER BY id`
+
+	args := []any{id, userID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var tasks []*Task
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		t, terr := scanTask(rows)
+		if terr != nil {
+			return nil, terr
+		}
+
+		tasks = append(tasks, t)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// parentProject returns the project of the parent task, making sure a child
+// added below it stays within MaxTaskDepth.
+func parentProject(ctx context.Context, tx pgx.Tx, parentID, userID string) (string, error) {
+	query := `WITH RECURSIVE ancestors AS (
+		SELECT id, parent_id, project_id, 1 AS depth
+		FROM tasks
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		UNION ALL
+		SELECT t.id, t.parent_id, t.project_id, a.depth + 1
+		FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+	)
+	SELECT (SELECT project_id FROM ancestors WHERE depth = 1), MAX(depth)
+	FROM ancestors`
+
+	var projectID *string
+	var depth *int
+
+	err := tx.QueryRow(ctx, query, parentID, userID).Scan(&projectID, &depth)
+	if err != nil {
+		return "", err
+	}
+
+	if projectID == nil || depth == nil {
+		return "", ErrParentNotFound
+	}
+
+	if *depth >= MaxTaskDepth {
+		return "", ErrTaskTooDeep
+	}
+
+	return *projectID, nil
+}
+
+// moveSubtasks moves every descendant of a task into projectID, recording the
+// move in their history. Trashed ones move too so that they are restored next
+// to their parent.
+func moveSubtasks(ctx context.Context, tx pgx.Tx, id, userID, projectID string) error {
+	query := `WITH RECURSIVE subtree AS (
+		SELECT id FROM tasks WHERE parent_id = $1
+		UNION ALL
+		SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+	)
+	SELECT ARRAY(SELECT tasks.id FROM tasks JOIN subtree s ON s.id = tasks.id
+		WHERE tasks.project_id <> $2 ORDER BY tasks.id)`
+
+	var ids []string
+
+	err := tx.QueryRow(ctx, query, id, projectID).Scan(&ids)
+	if err != nil {
+		return err
+	}
+
+	before, err := snapshotTasks(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE tasks
+	SET project_id = $2
+	WHERE id = ANY($1)`, ids, projectID)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "tasks_title_project_id_key"):
+			return ErrDuplicateTask
+		default:
+			return err
+		}
+	}
+
+	return recordTaskEvents(ctx, tx, ids, userID, TaskUpdated, before)
+}
+
+// completeSubtasks completes every open descendant of a task, recording the
+// completion in their history and adding the next occurrence of those that
+// recur. It fails with ErrOpenBlockers when one of them
+// is blocked by an open task outside the subtree, as those within it are
+// completed together.
+func completeSubtasks(ctx context.Context, tx pgx.Tx, id, userID string) error {
+	subtree := `WITH RECURSIVE subtree AS (
+		SELECT id FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL
+		UNION ALL
+		SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+		WHERE t.deleted_at IS NULL
+	)`
+
+	var blocked bool
+
+	err := tx.QueryRow(ctx, subtree+`
+	SELECT EXISTS (SELECT 1 FROM subtree s
+		JOIN tasks t ON t.id = s.id
+		JOIN task_dependencies d ON d.task_id = s.id
+		JOIN tasks b ON b.id = d.blocker_id
+		WHERE t.completed = false AND b.completed = false AND b.deleted_at IS NULL
+			AND b.id NOT IN (SELECT id FROM subtree))`, id).Scan(&blocked)
+	if err != nil {
+		return err
+	}
+
+	if blocked {
+		return ErrOpenBlockers
+	}
+
+	var ids []string
+
+	err = tx.QueryRow(ctx, subtree+`
+	SELECT ARRAY(SELECT tasks.id FROM tasks JOIN subtree s ON s.id = tasks.id
+		WHERE tasks.completed = false ORDER BY tasks.id)`, id).Scan(&ids)
+	if err != nil {
+		return err
+	}
+
+	before, err := snapshotTasks(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE tasks
+	SET completed = true, completed_at = now(), status = `+doneStatus+`
+	WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return err
+	}
+
+	err = recordTaskEvents(ctx, tx, ids, userID, TaskCompleted, before)
+	if err != nil {
+		return err
+	}
+
+	var recurring []string
+
+	err = tx.QueryRow(ctx, `SELECT ARRAY(SELECT id FROM tasks
+		WHERE id = ANY($1) AND recurrence IS NOT NULL ORDER BY id)`, ids).Scan(&recurring)
+	if err != nil {
+		return err
+	}
+
+	for _, rid := range recurring {
+		err = createNextOccurrence(ctx, tx, rid, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reopenAncestors reopens every completed ancestor of a task so that no
+// completed task is left with an open subtask, recording the change in their
+// history.
+func reopenAncestors(ctx context.Context, tx pgx.Tx, id, userID string) error {
+	query := `WITH RECURSIVE ancestors AS (
+		SELECT parent_id AS id FROM tasks WHERE id = $1
+		UNION ALL
+		SELECT t.parent_id FROM tasks t JOIN ancestors a ON t.id = a.id
+	)
+	SELECT ARRAY(SELECT tasks.id FROM tasks JOIN ancestors a ON a.id = tasks.id
+		WHERE tasks.completed = true ORDER BY tasks.id)`
+
+	var ids []string
+
+	err := tx.QueryRow(ctx, query, id).Scan(&ids)
+	if err != nil {
+		return err
+	}
+
+	before, err := snapshotTasks(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE tasks
+	SET completed = false, completed_at = NULL, archived_at = NULL, status = `+initialStatus+`
+	WHERE id = ANY($1)`, ids)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "tasks_title_project_id_key"):
+			return ErrDuplicateTask
+		default:
+			return err
+		}
+	}
+
+	return recordTaskEvents(ctx, tx, ids, userID, TaskUpdated, before)
+}
+
+// hasOpenSubtasks reports whether a task has any direct subtask left open.
+func hasOpenSubtasks(ctx context.Context, tx pgx.Tx, id string) (bool, error) {
+	var open bool
+
+	err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE parent_id = $1 AND completed = false AND deleted_at IS NULL)`, id).Scan(&open)
+	if err != nil {
+		return false, err
+	}
+
+	return open, nil
+}
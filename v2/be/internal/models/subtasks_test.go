@@ -0,0 +1,114 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func testSubtask(t *testing.T, tasks *models.TasksModel, userID string, parentID *string) *models.Task {
+	t.Helper()
+
+	task := &models.Task{
+		ID:          db.NewID(),
+		UserID:      userID,
+		ParentID:    parentID,
+		Title:       gofakeit.UUID(),
+		Description: gofakeit.Phrase(),
+	}
+
+	err := tasks.Create(context.Background(), task)
+	require.NoError(t, err)
+
+	return task
+}
+
+func TestTasksSubtasks(t *testing.T) {
+	t.Run("children and progress", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		root := testSubtask(t, tasks, u.ID, nil)
+
+		one := testSubtask(t, tasks, u.ID, &root.ID)
+		testSubtask(t, tasks, u.ID, &root.ID)
+		require.Equal(t, root.ProjectID, one.ProjectID)
+
+		err := tasks.Complete(context.Background(), one.ID, u.ID, 0, false)
+		require.NoError(t, err)
+
+		rt, err := tasks.GetByID(context.Background(), root.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.TaskProgress{Done: 1, Total: 2}, rt.Progress)
+
+		cs, err := tasks.Children(context.Background(), root.ID, u.ID)
+		require.NoError(t, err)
+		require.Len(t, cs, 2)
+		require.Equal(t, root.ID, *cs[0].ParentID)
+	})
+
+	t.Run("depth limit", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+
+		parent := testSubtask(t, tasks, u.ID, nil)
+		for i := 1; i < models.MaxTaskDepth; i++ {
+			parent = testSubtask(t, tasks, u.ID, &parent.ID)
+		}
+
+		task := &models.Task{
+			ID:          db.NewID(),
+			UserID:      u.ID,
+			ParentID:    &parent.ID,
+			Title:       gofakeit.UUID(),
+			Description: gofakeit.Phrase(),
+		}
+
+		err := tasks.Create(context.Background(), task)
+		require.ErrorIs(t, err, models.ErrTaskTooDeep)
+
+		missing := db.NewID()
+		task.ParentID = &missing
+
+		err = tasks.Create(context.Background(), task)
+		require.ErrorIs(t, err, models.ErrParentNotFound)
+	})
+
+	t.Run("complete parent", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		root := testSubtask(t, tasks, u.ID, nil)
+		child := testSubtask(t, tasks, u.ID, &root.ID)
+		grandchild := testSubtask(t, tasks, u.ID, &child.ID)
+
+		err := tasks.Complete(context.Background(), root.ID, u.ID, 0, false)
+		require.ErrorIs(t, err, models.ErrOpenSubtasks)
+
+		rt, err := tasks.GetByID(context.Background(), root.ID, u.ID)
+		require.NoError(t, err)
+		require.False(t, rt.Completed)
+
+		err = tasks.Complete(context.Background(), root.ID, u.ID, 0, true)
+		require.NoError(t, err)
+
+		rt, err = tasks.GetByID(context.Background(), grandchild.ID, u.ID)
+		require.NoError(t, err)
+		require.True(t, rt.Completed)
+	})
+}
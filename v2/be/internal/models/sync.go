@@ -0,0 +1,384 @@
+package models
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrInvalidSyncToken = errors.New("invalid sync token")
+	ErrTaskGone         = errors.New("task was deleted")
+	ErrIncompleteTask   = errors.New("a new task needs a title and a description")
+	ErrReminderAfterDue = errors.New("remind_at must not be after due_at")
+)
+
+// Operations accepted by Sync
+const (
+	SyncUpsert = "upsert"
+	SyncDelete = "delete"
+)
+
+// SyncOps lists the operations accepted by Sync
+var SyncOps = []string{SyncUpsert, SyncDelete}
+
+// SyncFields lists the fields of a task a client may change through Sync
+var SyncFields = []string{"title", "description", "due_at", "remind_at", "recurrence", "priority", "tags", "project_id", "completed"}
+
+// MaxSyncChanges is the largest number of changes a single Sync call may apply
+const MaxSyncChanges = 100
+
+// SyncToken marks the point a client synced up to. XID is the oldest
+// transaction still running when the changes were read, so writes committed
+// afterwards are never missed, and Time is the server time of the read.
+type SyncToken struct {
+	XID  uint64    `json:"x,string"`
+	Time time.Time `json:"t"`
+}
+
+// Encode returns an opaque, URL safe representation of the token
+func (s *SyncToken) Encode() string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeSyncToken parses a token previously produced by Encode
+func DecodeSyncToken(s string) (*SyncToken, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidSyncToken
+	}
+
+	var t SyncToken
+	err = json.Unmarshal(b, &t)
+	if err != nil || t.XID == 0 || t.Time.IsZero() {
+		return nil, ErrInvalidSyncToken
+	}
+
+	return &t, nil
+}
+
+// Tombstone reports a task that was deleted, whether to the trash or for good
+type Tombstone struct {
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// SyncChanges holds the tasks of a user written since a token and the token
+// to ask for the next changes with
+type SyncChanges struct {
+	Tasks   []*Task      `json:"tasks"`
+	Deleted []*Tombstone `json:"deleted"`
+	Token   string       `json:"token"`
+}
+
+// Changes returns the user's tasks created or changed since the given token
+// and tombstones for those deleted since then. A nil since returns every task
+// not in the trash. Tasks may be returned again by the next call, so clients
+// should apply them as they would any newer copy.
+func (m *TasksModel) Changes(ctx context.Context, userID string, since *SyncToken) (*SyncChanges, error) {
+	query := `SELECT ` + taskColumns + `
+	FROM tasks
+	WHERE user_id = $1 AND deleted_at IS NULL AND changed_xid >= $2::TEXT::XID8
+	ORDER BY id`
+
+	deleted := `SELECT id, deleted_at FROM tasks
+	WHERE user_id = $1 AND deleted_at IS NOT NULL AND changed_xid >= $2::TEXT::XID8
+	UNION ALL
+	SELECT task_id, deleted_at FROM task_tombstones
+	WHERE user_id = $1 AND changed_xid >= $2::TEXT::XID8
+	ORDER BY id`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var xmin string
+	next := &SyncToken{}
+
+	err = tx.QueryRow(ctx, `SELECT pg_snapshot_xmin(pg_current_snapshot())::TEXT, now()`).Scan(&xmin, &next.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	next.XID, err = strconv.ParseUint(xmin, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	from := "0"
+	if since != nil {
+		from = strconv.FormatUint(since.XID, 10)
+	}
+
+	rows, err := tx.Query(ctx, query, userID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &SyncChanges{Tasks: []*Task{}, Deleted: []*Tombstone{}, Token: next.Encode()}
+
+	for rows.Next() {
+		t, terr := scanTask(rows)
+		if terr != nil {
+			return nil, terr
+		}
+
+		changes.Tasks = append(changes.Tasks, t)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	if since != nil {
+		rows, err = tx.Query(ctx, deleted, userID, from)
+		if err != nil {
+			return nil, err
+		}
+
+		changes.Deleted, err = pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[Tombstone])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// SyncChange is a change made by a client, possibly while offline. For an
+// upsert, Task holds the new values of the fields named in Fields and the
+// others are left as they are. An upsert of an unknown id creates the task.
+type SyncChange struct {
+	Op     string
+	TaskID string
+	Fields []string
+	Task   *Task
+}
+
+// SyncResult is the outcome of a SyncChange, Err being nil when it was
+// applied. Conflicts names the fields the change overwrote although they had
+// changed on the server since the client's base token.
+type SyncResult struct {
+	TaskID    string
+	Op        string
+	Conflicts []string
+	Err       error
+}
+
+// Sync applies changes made by a client in order, each on its own so that a
+// failing change leaves the others applied. Conflicts are resolved per field,
+// the last write to reach the server winning, so a change only overwrites the
+// fields it holds. Deletes win over edits: a change to a deleted task fails
+// with ErrTaskGone, and deleting a task that is already gone succeeds.
+// A nil base reports no conflicts.
+func (m *TasksModel) Sync(ctx context.Context, userID string, base *SyncToken, changes []SyncChange) ([]*SyncResult, error) {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	results := make([]*SyncResult, 0, len(changes))
+
+	for _, c := range changes {
+		sp, serr := tx.Begin(ctx)
+		if serr != nil {
+			return nil, serr
+		}
+
+		conflicts, cerr := syncChange(ctx, sp, userID, base, c)
+		if cerr != nil {
+			conflicts = nil
+			serr = sp.Rollback(ctx)
+		} else {
+			serr = sp.Commit(ctx)
+		}
+		if serr != nil {
+			return nil, serr
+		}
+
+		results = append(results, &SyncResult{TaskID: c.TaskID, Op: c.Op, Conflicts: conflicts, Err: cerr})
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// syncChange applies a single change of Sync and returns its conflicting
+// fields, leaving out those written by earlier changes of the same call.
+func syncChange(ctx context.Context, tx pgx.Tx, userID string, base *SyncToken, c SyncChange) ([]string, error) {
+	var completed, trashed bool
+
+	err := tx.QueryRow(ctx, `SELECT completed, deleted_at IS NOT NULL FROM tasks
+	WHERE id = $1 AND user_id = $2`, c.TaskID, userID).Scan(&completed, &trashed)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, syncNewTask(ctx, tx, userID, c)
+	}
+
+	switch {
+	case trashed && c.Op == SyncDelete:
+		return nil, nil
+	case trashed:
+		return nil, ErrTaskGone
+	case c.Op == SyncDelete:
+		return nil, deleteTask(ctx, tx, c.TaskID, userID)
+	}
+
+	var conflicts []string
+
+	if base != nil {
+		err = tx.QueryRow(ctx, `SELECT ARRAY(
+			SELECT key FROM tasks, jsonb_each_text(field_changed_at)
+			WHERE id = $1 AND key = ANY($2) AND value::TIMESTAMPTZ > $3
+				AND value::TIMESTAMPTZ <> now()
+			ORDER BY key
+		)`, c.TaskID, c.Fields, base.Time).Scan(&conflicts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	complete := slices.Contains(c.Fields, "completed")
+
+	if complete && completed && !c.Task.Completed {
+		err = reopenTask(ctx, tx, c.TaskID, userID, "")
+		if err != nil {
+			return nil, err
+		}
+
+		completed = false
+	}
+
+	edits := slices.DeleteFunc(slices.Clone(c.Fields), func(f string) bool { return f == "completed" })
+
+	if len(edits) > 0 {
+		if completed {
+			return nil, ErrTaskCompleted
+		}
+
+		t, serr := scanTask(tx.QueryRow(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = $1`, c.TaskID))
+		if serr != nil {
+			return nil, serr
+		}
+
+		mergeTask(t, c.Task, edits)
+
+		if t.DueAt != nil && t.RemindAt != nil && t.RemindAt.After(*t.DueAt) {
+			return nil, ErrReminderAfterDue
+		}
+
+		err = updateTask(ctx, tx, t)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if complete && !completed && c.Task.Completed {
+		err = completeTask(ctx, tx, c.TaskID, userID, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return conflicts, nil
+}
+
+// syncNewTask applies a change to a task the user does not have, creating it
+// unless it was deleted for good or belongs to someone else.
+func syncNewTask(ctx context.Context, tx pgx.Tx, userID string, c SyncChange) error {
+	var taken, gone bool
+
+	err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1),
+		EXISTS (SELECT 1 FROM task_tombstones WHERE task_id = $1 AND user_id = $2)`, c.TaskID, userID).Scan(&taken, &gone)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case taken:
+		return ErrRecordNotFound
+	case c.Op == SyncDelete:
+		return nil
+	case gone:
+		return ErrTaskGone
+	}
+
+	t := &Task{ID: c.TaskID, UserID: userID}
+	mergeTask(t, c.Task, c.Fields)
+
+	if t.Title == "" || t.Description == "" {
+		return ErrIncompleteTask
+	}
+
+	if t.DueAt != nil && t.RemindAt != nil && t.RemindAt.After(*t.DueAt) {
+		return ErrReminderAfterDue
+	}
+
+	return createTask(ctx, tx, t)
+}
+
+// mergeTask copies the named fields from src to dst. Tags are only written
+// when named, so dst.Tags is cleared otherwise.
+func mergeTask(dst, src *Task, fields []string) {
+	dst.Tags = nil
+
+	for _, field := range fields {
+		switch field {
+		case "title":
+			dst.Title = src.Title
+		case "description":
+			dst.Description = src.Description
+		case "due_at":
+			dst.DueAt = src.DueAt
+		case "remind_at":
+			dst.RemindAt = src.RemindAt
+		case "recurrence":
+			dst.Recurrence = src.Recurrence
+		case "priority":
+			dst.Priority = src.Priority
+		case "tags":
+			dst.Tags = src.Tags
+		case "project_id":
+			dst.ProjectID = src.ProjectID
+		case "completed":
+			dst.Completed = src.Completed
+		}
+	}
+}
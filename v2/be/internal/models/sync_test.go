@@ -0,0 +1,139 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func taskIDs(tasks []*models.Task) []string {
+	var ids []string
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+
+	return ids
+}
+
+func tombstoneIDs(deleted []*models.Tombstone) []string {
+	var ids []string
+	for _, d := range deleted {
+		ids = append(ids, d.ID)
+	}
+
+	return ids
+}
+
+func TestTasksChanges(t *testing.T) {
+	t.Run("since token", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		kept := testSubtask(t, tasks, u.ID, nil)
+		edited := testSubtask(t, tasks, u.ID, nil)
+		trashed := testSubtask(t, tasks, u.ID, nil)
+
+		first, err := tasks.Changes(context.Background(), u.ID, nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{kept.ID, edited.ID, trashed.ID}, taskIDs(first.Tasks))
+		require.Empty(t, first.Deleted)
+
+		since, err := models.DecodeSyncToken(first.Token)
+		require.NoError(t, err)
+
+		edited.Title = "Edited"
+		require.NoError(t, tasks.Update(context.Background(), edited))
+		require.NoError(t, tasks.Delete(context.Background(), trashed.ID, u.ID, 0))
+		added := testSubtask(t, tasks, u.ID, nil)
+
+		next, err := tasks.Changes(context.Background(), u.ID, since)
+		require.NoError(t, err)
+		require.Subset(t, taskIDs(next.Tasks), []string{edited.ID, added.ID})
+		require.NotContains(t, taskIDs(next.Tasks), trashed.ID)
+		require.Equal(t, []string{trashed.ID}, tombstoneIDs(next.Deleted))
+
+		_, err = tasks.EmptyTrash(context.Background(), u.ID)
+		require.NoError(t, err)
+
+		last, err := tasks.Changes(context.Background(), u.ID, since)
+		require.NoError(t, err)
+		require.Equal(t, []string{trashed.ID}, tombstoneIDs(last.Deleted))
+	})
+}
+
+func TestTasksSync(t *testing.T) {
+	t.Run("changes", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		u := testUser(t, users)
+		other := testUser(t, users)
+
+		tasks := &models.TasksModel{Pool: pool}
+		open := testSubtask(t, tasks, u.ID, nil)
+		trashed := testSubtask(t, tasks, u.ID, nil)
+		theirs := testSubtask(t, tasks, other.ID, nil)
+
+		changes, err := tasks.Changes(context.Background(), u.ID, nil)
+		require.NoError(t, err)
+
+		base, err := models.DecodeSyncToken(changes.Token)
+		require.NoError(t, err)
+
+		// another client edits the title after the base token was read
+		edited := *open
+		edited.Description = "Changed elsewhere"
+		edited.Title = "Elsewhere"
+		require.NoError(t, tasks.Update(context.Background(), &edited))
+		require.NoError(t, tasks.Delete(context.Background(), trashed.ID, u.ID, 0))
+
+		created := db.NewID()
+
+		results, err := tasks.Sync(context.Background(), u.ID, base, []models.SyncChange{
+			{Op: models.SyncUpsert, TaskID: created, Fields: []string{"title", "description", "tags"},
+				Task: &models.Task{Title: "Offline", Description: "Made offline", Tags: []string{"mobile"}}},
+			{Op: models.SyncUpsert, TaskID: open.ID, Fields: []string{"title", "priority"},
+				Task: &models.Task{Title: "Offline title", Priority: "high"}},
+			{Op: models.SyncUpsert, TaskID: open.ID, Fields: []string{"title", "completed"},
+				Task: &models.Task{Title: "Done offline", Completed: true}},
+			{Op: models.SyncUpsert, TaskID: trashed.ID, Fields: []string{"title"}, Task: &models.Task{Title: "Too late"}},
+			{Op: models.SyncDelete, TaskID: trashed.ID},
+			{Op: models.SyncDelete, TaskID: theirs.ID},
+			{Op: models.SyncUpsert, TaskID: db.NewID(), Fields: []string{"title"}, Task: &models.Task{Title: "No description"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 7)
+
+		require.NoError(t, results[0].Err)
+		require.NoError(t, results[1].Err)
+		require.Equal(t, []string{"title"}, results[1].Conflicts)
+		require.NoError(t, results[2].Err)
+		require.Empty(t, results[2].Conflicts)
+		require.ErrorIs(t, results[3].Err, models.ErrTaskGone)
+		require.NoError(t, results[4].Err)
+		require.ErrorIs(t, results[5].Err, models.ErrRecordNotFound)
+		require.ErrorIs(t, results[6].Err, models.ErrIncompleteTask)
+
+		rt, err := tasks.GetByID(context.Background(), created, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"mobile"}, rt.Tags)
+
+		rt, err = tasks.GetByID(context.Background(), open.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Done offline", rt.Title)
+		require.Equal(t, "Changed elsewhere", rt.Description)
+		require.Equal(t, "high", rt.Priority)
+		require.True(t, rt.Completed)
+
+		_, err = tasks.GetByID(context.Background(), theirs.ID, other.ID)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,318 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"v2/be/internal/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrDuplicateTag = errors.New("tag exists")
+
+type Tag struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+type TagsModel struct {
+	Pool *pgxpool.Pool
+}
+
+func (m *TagsModel) Create(ctx context.Context, t *Tag) error {
+	query := `INSERT INTO tags (id, user_id, name)
+	VALUES ($1, $2, $3)`
+
+	args := []any{t.ID, t.UserID, t.Name}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "tags_name_user_id_key"):
+			return ErrDuplicateTag
+		default:
+			return err
+		}
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrOpFailed
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *TagsModel) All(ctx context.Context, userID string) ([]*Tag, error) {
+	query := `SELECT id, user_id, name
+	FROM tags
+	WHERE user_id = $1
+	ORDER BY name`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var tags []*Tag
+
+	rows, err := tx.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var t Tag
+		terr := rows.Scan(
+			&t.ID,
+			&t.UserID,
+			&t.Name,
+		)
+
+		if terr != nil {
+			return nil, terr
+		}
+
+		tags = append(tags, &t)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (m *TagsModel) GetByID(ctx context.Context, id, userID string) (*Tag, error) {
+	query := `SELECT id, user_id, name
+	FROM tags
+	WHERE id = $1 AND user_id = $2`
+
+	args := []any{id, userID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var t Tag
+
+	err = tx.QueryRow(ctx, query, args...).Scan(
+		&t.ID,
+		&t.UserID,
+		&t.Name,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (m *TagsModel) Update(ctx context.Context, t *Tag) error {
+	query := `UPDATE tags
+	SET name = $1
+	WHERE id = $2 AND user_id = $3`
+
+	args := []any{t.Name, t.ID, t.UserID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "tags_name_user_id_key"):
+			return ErrDuplicateTag
+		default:
+			return err
+		}
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrOpFailed
+	}
+
+	err = touchTagTasks(ctx, tx, t.ID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *TagsModel) Delete(ctx context.Context, id, userID string) error {
+	query := `DELETE FROM tags
+	WHERE id = $1 AND user_id = $2`
+
+	args := []any{id, userID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	// the tagged tasks have to be touched before the delete cascades to task_tags
+	err = touchTagTasks(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrOpFailed
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setTaskTags replaces the tags of a task with names, creating any tag the
+// user does not have yet.
+func setTaskTags(ctx context.Context, tx pgx.Tx, taskID, userID string, names []string) error {
+	for _, name := range names {
+		_, err := tx.Exec(ctx, `INSERT INTO tags (id, user_id, name)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name, user_id) DO NOTHING`, db.NewID(), userID, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(ctx, `DELETE FROM task_tags WHERE task_id = $1`, taskID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO task_tags (task_id, tag_id)
+	SELECT $1, id FROM tags
+	WHERE user_id = $2 AND name = ANY($3)`, taskID, userID, names)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addTaskTags adds names to the tags of a task, creating any tag the user does
+// not have yet.
+func addTaskTags(ctx context.Context, tx pgx.Tx, taskID, userID string, names []string) error {
+	for _, name := range names {
+		_, err := tx.Exec(ctx, `INSERT INTO tags (id, user_id, name)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name, user_id) DO NOTHING`, db.NewID(), userID, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(ctx, `INSERT INTO task_tags (task_id, tag_id)
+	SELECT $1, id FROM tags
+	WHERE user_id = $2 AND name = ANY($3)
+	ON CONFLICT (task_id, tag_id) DO NOTHING`, taskID, userID, names)
+	if err != nil {
+		return err
+	}
+
+	return touchTaskTags(ctx, tx, taskID)
+}
+
+// touchTaskTags records that the tags of a task changed. Tags are part of the
+// task, so the task moves to a new version too.
+func touchTaskTags(ctx context.Context, tx pgx.Tx, taskID string) error {
+	_, err := tx.Exec(ctx, `UPDATE tasks
+	SET field_changed_at = field_changed_at || jsonb_build_object('tags', now())
+	WHERE id = $1`, taskID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// touchTagTasks records that the tags of every task tagged with tagID changed,
+// as renaming or deleting the tag shows up on each of them
+func touchTagTasks(ctx context.Context, tx pgx.Tx, tagID string) error {
+	_, err := tx.Exec(ctx, `UPDATE tasks
+	SET field_changed_at = field_changed_at || jsonb_build_object('tags', now())
+	WHERE id IN (SELECT task_id FROM task_tags WHERE tag_id = $1)`, tagID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
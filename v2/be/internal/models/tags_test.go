@@ -0,0 +1,218 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func testUser(t *testing.T, users *models.UsersModel) *models.User {
+	t.Helper()
+
+	u := &models.User{
+		ID:       db.NewID(),
+		Username: gofakeit.Username(),
+		Password: []byte(testUserPassword(t)),
+	}
+
+	err := users.Create(context.Background(), u)
+	require.NoError(t, err)
+
+	return u
+}
+
+func TestTagsCreate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tags := &models.TagsModel{Pool: pool}
+		tag := &models.Tag{
+			ID:     db.NewID(),
+			UserID: u.ID,
+			Name:   "work",
+		}
+
+		err := tags.Create(context.Background(), tag)
+		require.NoError(t, err)
+
+		rt, err := tags.GetByID(context.Background(), tag.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, tag.Name, rt.Name)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tags := &models.TagsModel{Pool: pool}
+
+		err := tags.Create(context.Background(), &models.Tag{ID: db.NewID(), UserID: u.ID, Name: "home"})
+		require.NoError(t, err)
+
+		err = tags.Create(context.Background(), &models.Tag{ID: db.NewID(), UserID: u.ID, Name: "home"})
+		require.ErrorIs(t, err, models.ErrDuplicateTag)
+	})
+}
+
+func TestTagsAll(t *testing.T) {
+	t.Parallel()
+
+	pool := testPool(t)
+	u := testUser(t, &models.UsersModel{Pool: pool})
+
+	tags := &models.TagsModel{Pool: pool}
+	for _, name := range []string{"work", "home", "errands"} {
+		err := tags.Create(context.Background(), &models.Tag{ID: db.NewID(), UserID: u.ID, Name: name})
+		require.NoError(t, err)
+	}
+
+	ts, err := tags.All(context.Background(), u.ID)
+	require.NoError(t, err)
+	require.Len(t, ts, 3)
+	require.Equal(t, "errands", ts[0].Name)
+
+	ts, err = tags.All(context.Background(), db.NewID())
+	require.NoError(t, err)
+	require.Empty(t, ts)
+}
+
+func TestTagsUpdate(t *testing.T) {
+	t.Parallel()
+
+	pool := testPool(t)
+	u := testUser(t, &models.UsersModel{Pool: pool})
+
+	tags := &models.TagsModel{Pool: pool}
+	one := &models.Tag{ID: db.NewID(), UserID: u.ID, Name: "one"}
+	two := &models.Tag{ID: db.NewID(), UserID: u.ID, Name: "two"}
+
+	require.NoError(t, tags.Create(context.Background(), one))
+	require.NoError(t, tags.Create(context.Background(), two))
+
+	tasks := &models.TasksModel{Pool: pool}
+	task := &models.Task{
+		ID:          db.NewID(),
+		UserID:      u.ID,
+		Title:       gofakeit.BookTitle(),
+		Description: gofakeit.Phrase(),
+		Tags:        []string{"one"},
+	}
+	require.NoError(t, tasks.Create(context.Background(), task))
+
+	before, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+	require.NoError(t, err)
+
+	one.Name = "uno"
+	err = tags.Update(context.Background(), one)
+	require.NoError(t, err)
+
+	after, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"uno"}, after.Tags)
+	require.Greater(t, after.Version, before.Version)
+
+	two.Name = "uno"
+	err = tags.Update(context.Background(), two)
+	require.ErrorIs(t, err, models.ErrDuplicateTag)
+
+	err = tags.Update(context.Background(), &models.Tag{ID: db.NewID(), UserID: u.ID, Name: "tres"})
+	require.ErrorIs(t, err, models.ErrOpFailed)
+}
+
+func TestTagsDelete(t *testing.T) {
+	t.Parallel()
+
+	pool := testPool(t)
+	u := testUser(t, &models.UsersModel{Pool: pool})
+
+	tags := &models.TagsModel{Pool: pool}
+	tag := &models.Tag{ID: db.NewID(), UserID: u.ID, Name: "work"}
+	require.NoError(t, tags.Create(context.Background(), tag))
+
+	err := tags.Delete(context.Background(), tag.ID, db.NewID())
+	require.ErrorIs(t, err, models.ErrOpFailed)
+
+	tasks := &models.TasksModel{Pool: pool}
+	task := &models.Task{
+		ID:          db.NewID(),
+		UserID:      u.ID,
+		Title:       gofakeit.BookTitle(),
+		Description: gofakeit.Phrase(),
+		Tags:        []string{"work"},
+	}
+	require.NoError(t, tasks.Create(context.Background(), task))
+
+	before, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+	require.NoError(t, err)
+
+	err = tags.Delete(context.Background(), tag.ID, u.ID)
+	require.NoError(t, err)
+
+	after, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+	require.NoError(t, err)
+	require.Empty(t, after.Tags)
+	require.Greater(t, after.Version, before.Version)
+
+	_, err = tags.GetByID(context.Background(), tag.ID, u.ID)
+	require.ErrorIs(t, err, models.ErrRecordNotFound)
+}
+
+func TestTaskTags(t *testing.T) {
+	t.Parallel()
+
+	pool := testPool(t)
+	u := testUser(t, &models.UsersModel{Pool: pool})
+
+	tasks := &models.TasksModel{Pool: pool}
+	task := &models.Task{
+		ID:          db.NewID(),
+		UserID:      u.ID,
+		Title:       gofakeit.BookTitle(),
+		Description: gofakeit.Phrase(),
+		Tags:        []string{"work", "urgent"},
+	}
+
+	err := tasks.Create(context.Background(), task)
+	require.NoError(t, err)
+
+	other := &models.Task{
+		ID:          db.NewID(),
+		UserID:      u.ID,
+		Title:       gofakeit.BookTitle(),
+		Description: gofakeit.Phrase(),
+	}
+
+	err = tasks.Create(context.Background(), other)
+	require.NoError(t, err)
+
+	rt, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"urgent", "work"}, rt.Tags)
+
+	ts, _, err := tasks.All(context.Background(), u.ID, models.TaskFilter{Tag: "work"})
+	require.NoError(t, err)
+	require.Len(t, ts, 1)
+	require.Equal(t, task.ID, ts[0].ID)
+
+	rt.Tags = []string{"home"}
+	err = tasks.Update(context.Background(), rt)
+	require.NoError(t, err)
+
+	rt, err = tasks.GetByID(context.Background(), task.ID, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"home"}, rt.Tags)
+
+	all, err := (&models.TagsModel{Pool: pool}).All(context.Background(), u.ID)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"v2/be/internal/db"
 
@@ -11,26 +12,252 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var ErrDuplicateTask = errors.New("task exist")
+var (
+	ErrDuplicateTask = errors.New("task exist")
+	ErrEditConflict  = errors.New("task was changed since it was read")
+)
+
+const (
+	DefaultTaskLimit = 20
+	MaxTaskLimit     = 100
+)
+
+// TaskSorts lists the accepted TaskFilter.Sort values, a leading "-" sorts in descending order.
+// "position" is the manual order set by moving tasks around.
+var TaskSorts = []string{"position", "-position", "created", "-created", "title", "-title", "priority", "-priority"}
+
+// TaskPriorities lists the priority levels of a task from lowest to highest
+var TaskPriorities = []string{"none", "low", "medium", "high", "urgent"}
+
+// TaskFilter narrows and orders the tasks returned by All
+type TaskFilter struct {
+	ProjectID   string
+	Completed   *bool
+	Query       string
+	Tag         string
+	Assigned    bool
+	WorkspaceID string
+	Archived    bool
+	Sort        string
+	Limit       int
+	Cursor      *Cursor
+}
+
+func (f *TaskFilter) limit() int {
+	if f.Limit <= 0 {
+		return DefaultTaskLimit
+	}
+
+	return f.Limit
+}
+
+// sortColumn returns the column backing the sort and whether it is descending.
+// Ids are UUIDv7 so ordering by id orders by creation time.
+func (f *TaskFilter) sortColumn() (string, bool) {
+	desc := strings.HasPrefix(f.Sort, "-")
+
+	switch s := strings.TrimPrefix(f.Sort, "-"); s {
+	case "title", "position", "priority":
+		return s, desc
+	default:
+		return "id", desc
+	}
+}
+
+// cursor returns the cursor pointing at t under the filter sort
+func (f *TaskFilter) cursor(t *Task) *Cursor {
+	c := &Cursor{Sort: f.Sort, ID: t.ID}
+
+	column, _ := f.sortColumn()
+	switch column {
+	case "title":
+		c.Key = t.Title
+	case "position":
+		c.Key = t.Position
+	case "priority":
+		c.Key = t.Priority
+	}
+
+	return c
+}
+
+// build returns the list query for userID and its arguments
+func (f *TaskFilter) build(userID string) (string, []any) {
+	args := queryArgs{}
+
+	var b strings.Builder
+	b.WriteString(`SELECT ` + taskColumns + `
+	FROM tasks
+	WHERE deleted_at IS NULL`)
+
+	// archived tasks are only listed on their own
+	if f.Archived {
+		b.WriteString(` AND archived_at IS NOT NULL`)
+	} else {
+		b.WriteString(` AND archived_at IS NULL`)
+	}
+
+	// tasks shared with the user are listed among their own, while the tasks
+	// of a workspace or assigned to the user are listed whoever owns them
+	u := args.add(userID)
+	switch {
+	case f.WorkspaceID != "":
+		b.WriteString(` AND workspace_id = ` + args.add(f.WorkspaceID))
+	case !f.Assigned:
+		b.WriteString(` AND (user_id = ` + u + ` OR id IN (` + sharedTasks(u) + `))`)
+	}
+
+	if f.Assigned {
+		b.WriteString(` AND assignee_id = ` + u)
+	}
+
+	if f.ProjectID != "" {
+		b.WriteString(` AND project_id = ` + args.add(f.ProjectID))
+	}
+
+	if f.Completed != nil {
+		b.WriteString(` AND completed = ` + args.add(*f.Completed))
+	}
+
+	if f.Query != "" {
+		p := args.add(containsPattern(f.Query))
+		b.WriteString(` AND (title ILIKE ` + p + ` OR description ILIKE ` + p + `)`)
+	}
+
+	if f.Tag != "" {
+		b.WriteString(` AND EXISTS (SELECT 1 FROM task_tags JOIN tags ON tags.id = task_tags.tag_id
+		WHERE task_tags.task_id = tasks.id AND tags.name = ` + args.add(f.Tag) + `)`)
+	}
+
+	column, desc := f.sortColumn()
+
+	op, dir := ">", "ASC"
+	if desc {
+		op, dir = "<", "DESC"
+	}
+
+	if f.Cursor != nil {
+		if column == "id" {
+			b.WriteString(` AND id ` + op + ` ` + args.add(f.Cursor.ID))
+		} else {
+			key := args.add(f.Cursor.Key)
+			if column == "priority" {
+				key += `::task_priority`
+			}
+
+			b.WriteString(` AND (` + column + `, id) ` + op + ` (` + key + `, ` + args.add(f.Cursor.ID) + `)`)
+		}
+	}
+
+	if column == "id" {
+		b.WriteString(`
+	ORDER BY id ` + dir)
+	} else {
+		b.WriteString(`
+	ORDER BY ` + column + ` ` + dir + `, id ` + dir)
+	}
+
+	// one extra row tells us whether another page exists
+	b.WriteString(`
+	LIMIT ` + args.add(f.limit()+1))
+
+	return b.String(), args
+}
 
 type Task struct {
-	ID          string `json:"id"`
-	UserID      string `json:"user_id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Completed   bool   `json:"completed"`
+	ID          string       `json:"id"`
+	UserID      string       `json:"user_id"`
+	ProjectID   string       `json:"project_id"`
+	ParentID    *string      `json:"parent_id"`
+	AssigneeID  *string      `json:"assignee_id"`
+	WorkspaceID *string      `json:"workspace_id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Status      string       `json:"status"`
+	Completed   bool         `json:"completed"`
+	CompletedAt *time.Time   `json:"completed_at"`
+	DeletedAt   *time.Time   `json:"deleted_at,omitempty"`
+	ArchivedAt  *time.Time   `json:"archived_at"`
+	DueAt       *time.Time   `json:"due_at"`
+	RemindAt    *time.Time   `json:"remind_at"`
+	Recurrence  *string      `json:"recurrence"`
+	Priority    string       `json:"priority"`
+	Position    string       `json:"position"`
+	Version     int64        `json:"version"`
+	Overdue     bool         `json:"overdue"`
+	Tags        []string     `json:"tags"`
+	Progress    TaskProgress `json:"progress"`
+	BlockedBy   []string     `json:"blocked_by"`
+	Blocking    []string     `json:"blocking"`
+	Subtasks    []*Task      `json:"subtasks,omitempty"`
 }
 
-type TasksModel struct {
-	Pool *pgxpool.Pool
+// IsOverdue reports whether the task is still open after its due date
+func (t *Task) IsOverdue(now time.Time) bool {
+	return !t.Completed && t.DueAt != nil && t.DueAt.Before(now)
 }
 
-func (m *TasksModel) Create(ctx context.Context, t *Task) error {
-	query := `INSERT INTO tasks (id, user_id, title, description, completed)
-	VALUES ($1, $2, $3, $4, $5)`
+// taskColumns lists the columns read by scanTask, in scan order
+const taskColumns = `id, user_id, project_id, parent_id, assignee_id, workspace_id, title, description, status, completed, completed_at, deleted_at, archived_at, due_at, remind_at, recurrence, priority::TEXT, position, version,
+	ARRAY(SELECT tags.name FROM task_tags JOIN tags ON tags.id = task_tags.tag_id
+		WHERE task_tags.task_id = tasks.id ORDER BY tags.name),
+	(SELECT count(*) FILTER (WHERE c.completed) FROM tasks c WHERE c.parent_id = tasks.id AND c.deleted_at IS NULL),
+	(SELECT count(*) FROM tasks c WHERE c.parent_id = tasks.id AND c.deleted_at IS NULL),
+	ARRAY(SELECT d.blocker_id FROM task_dependencies d JOIN tasks b ON b.id = d.blocker_id
+		WHERE d.task_id = tasks.id AND b.deleted_at IS NULL ORDER BY d.blocker_id),
+	ARRAY(SELECT d.task_id FROM task_dependencies d JOIN tasks b ON b.id = d.task_id
+		WHERE d.blocker_id = tasks.id AND b.deleted_at IS NULL ORDER BY d.task_id)`
+
+// scanTask reads a row selected with taskColumns, followed by any extra columns
+func scanTask(row pgx.Row, extra ...any) (*Task, error) {
+	var t Task
+
+	dest := []any{
+		&t.ID,
+		&t.UserID,
+		&t.ProjectID,
+		&t.ParentID,
+		&t.AssigneeID,
+		&t.WorkspaceID,
+		&t.Title,
+		&t.Description,
+		&t.Status,
+		&t.Completed,
+		&t.CompletedAt,
+		&t.DeletedAt,
+		&t.ArchivedAt,
+		&t.DueAt,
+		&t.RemindAt,
+		&t.Recurrence,
+		&t.Priority,
+		&t.Position,
+		&t.Version,
+		&t.Tags,
+		&t.Progress.Done,
+		&t.Progress.Total,
+		&t.BlockedBy,
+		&t.Blocking,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Overdue = t.IsOverdue(time.Now())
+
+	return &t, nil
+}
 
-	args := []any{t.ID, t.UserID, t.Title, t.Description, t.Completed}
+type TasksModel struct {
+	Pool  *pgxpool.Pool
+	Blobs BlobStore
+}
 
+// Create inserts t into its project, or into the user's inbox when t.ProjectID is empty.
+// Subtasks always live in the project of their parent, and in its workspace
+// when t.WorkspaceID is nil.
+func (m *TasksModel) Create(ctx context.Context, t *Task) error {
 	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
 		IsoLevel:       pgx.Serializable,
 		AccessMode:     pgx.ReadWrite,
@@ -42,32 +269,151 @@ func (m *TasksModel) Create(ctx context.Context, t *Task) error {
 
 	defer tx.Rollback(ctx)
 
-	result, err := tx.Exec(ctx, query, args...)
+	err = createTask(ctx, tx, t)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// createTask does the work of Create.
+func createTask(ctx context.Context, tx pgx.Tx, t *Task) error {
+	query := `INSERT INTO tasks (id, user_id, project_id, title, description, completed, completed_at, due_at, remind_at, parent_id, recurrence, priority, position, status, workspace_id)
+	SELECT $1::TEXT, $2::TEXT, id, $4::TEXT, $5::TEXT, $6::BOOLEAN, CASE WHEN $6 THEN now() END, $7::TIMESTAMPTZ, $8::TIMESTAMPTZ, $9::TEXT, $10::TEXT,
+		COALESCE(NULLIF($11::TEXT, ''), 'none')::task_priority, $12::TEXT,
+		(SELECT name FROM statuses WHERE user_id = $2 AND done = $6 ORDER BY position LIMIT 1),
+		COALESCE($13::TEXT, (SELECT workspace_id FROM tasks WHERE id = $9))
+	FROM projects
+	WHERE id = $3 AND user_id = $2
+	RETURNING version`
+
+	var err error
+
+	switch {
+	case t.ParentID != nil:
+		t.ProjectID, err = parentProject(ctx, tx, *t.ParentID, t.UserID)
+		if err != nil {
+			return err
+		}
+	case t.ProjectID == "":
+		t.ProjectID, err = ensureInbox(ctx, tx, t.UserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = ensureWorkflow(ctx, tx, t.UserID)
+	if err != nil {
+		return err
+	}
+
+	t.Position, err = lastPosition(ctx, tx, t.UserID)
+	if err != nil {
+		return err
+	}
+
+	if t.Priority == "" {
+		t.Priority = TaskPriorities[0]
+	}
+
+	args := []any{t.ID, t.UserID, t.ProjectID, t.Title, t.Description, t.Completed, t.DueAt, t.RemindAt, t.ParentID, t.Recurrence, t.Priority, t.Position, t.WorkspaceID}
+
+	err = tx.QueryRow(ctx, query, args...).Scan(&t.Version)
 	if err != nil {
 		switch {
-		case strings.Contains(db.FormatErr(err), "tasks_title_user_id_key"):
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrProjectNotFound
+		case strings.Contains(db.FormatErr(err), "tasks_title_project_id_key"):
 			return ErrDuplicateTask
 		default:
 			return err
 		}
 	}
 
-	if result.RowsAffected() != 1 {
-		return ErrOpFailed
+	if t.Tags != nil {
+		err = setTaskTags(ctx, tx, t.ID, t.UserID, t.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	return recordTaskEvent(ctx, tx, t.ID, t.UserID, TaskCreated, nil)
+}
+
+// All returns a page of the user's tasks matching f and the cursor of the
+// next page, which is empty on the last page
+func (m *TasksModel) All(ctx context.Context, userID string, f TaskFilter) ([]*Task, string, error) {
+	query, args := f.build(userID)
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var tasks []*Task
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for rows.Next() {
+		t, terr := scanTask(rows)
+		if terr != nil {
+			return nil, "", terr
+		}
+
+		tasks = append(tasks, t)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, "", err
 	}
 
 	err = tx.Commit(ctx)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
-	return nil
+	var next string
+	if len(tasks) > f.limit() {
+		tasks = tasks[:f.limit()]
+		next = f.cursor(tasks[len(tasks)-1]).Encode()
+	}
+
+	return tasks, next, nil
 }
 
-func (m *TasksModel) All(ctx context.Context, userID string) ([]*Task, error) {
-	query := `SELECT id, title, description, completed
-	FROM tasks
-	WHERE user_id = $1`
+// TaskMatch is a task returned by Search with its relevance and a highlighted excerpt
+type TaskMatch struct {
+	*Task
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// Search returns up to limit of the user's tasks matching q, best matches first
+func (m *TasksModel) Search(ctx context.Context, userID, q string, limit int) ([]*TaskMatch, error) {
+	query := `SELECT ` + taskColumns + `, ts_rank(search, q) AS rank,
+		ts_headline('english', title || ' ' || description, q, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2')
+	FROM tasks, websearch_to_tsquery('english', $2) q
+	WHERE user_id = $1 AND deleted_at IS NULL AND search @@ q
+	ORDER BY rank DESC, id
+	LIMIT $3`
+
+	args := []any{userID, q, limit}
 
 	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
 		IsoLevel:       pgx.Serializable,
@@ -80,27 +426,23 @@ func (m *TasksModel) All(ctx context.Context, userID string) ([]*Task, error) {
 
 	defer tx.Rollback(ctx)
 
-	var tasks []*Task
+	var matches []*TaskMatch
 
-	rows, err := tx.Query(ctx, query, userID)
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 
 	for rows.Next() {
-		var t Task
-		terr := rows.Scan(
-			&t.ID,
-			&t.Title,
-			&t.Description,
-			&t.Completed,
-		)
+		var tm TaskMatch
 
+		t, terr := scanTask(rows, &tm.Rank, &tm.Snippet)
 		if terr != nil {
 			return nil, terr
 		}
 
-		tasks = append(tasks, &t)
+		tm.Task = t
+		matches = append(matches, &tm)
 	}
 
 	err = rows.Err()
@@ -113,13 +455,13 @@ func (m *TasksModel) All(ctx context.Context, userID string) ([]*Task, error) {
 		return nil, err
 	}
 
-	return tasks, nil
+	return matches, nil
 }
 
 func (m *TasksModel) GetByID(ctx context.Context, id, userID string) (*Task, error) {
-	query := `SELECT id, title, description, completed
+	query := `SELECT ` + taskColumns + `
 	FROM tasks
-	WHERE id = $1 AND user_id = $2`
+	WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
 
 	args := []any{id, userID}
 
@@ -134,14 +476,7 @@ func (m *TasksModel) GetByID(ctx context.Context, id, userID string) (*Task, err
 
 	defer tx.Rollback(ctx)
 
-	var t Task
-
-	err = tx.QueryRow(ctx, query, args...).Scan(
-		&t.ID,
-		&t.Title,
-		&t.Description,
-		&t.Completed,
-	)
+	t, err := scanTask(tx.QueryRow(ctx, query, args...))
 	if err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
@@ -156,16 +491,13 @@ func (m *TasksModel) GetByID(ctx context.Context, id, userID string) (*Task, err
 		return nil, err
 	}
 
-	return &t, nil
+	return t, nil
 }
 
+// Update saves the editable fields of an open task. An empty t.ProjectID keeps the task in its current project.
+// It fails with ErrEditConflict unless the task is still at t.Version, which is then set to the new version.
+// A zero t.Version skips the check.
 func (m *TasksModel) Update(ctx context.Context, t *Task) error {
-	query := `UPDATE tasks
-	SET title = $1, description = $2
-	WHERE id = $3 AND completed = false`
-
-	args := []any{t.Title, t.Description, t.ID}
-
 	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
 		IsoLevel:       pgx.Serializable,
 		AccessMode:     pgx.ReadWrite,
@@ -177,18 +509,103 @@ func (m *TasksModel) Update(ctx context.Context, t *Task) error {
 
 	defer tx.Rollback(ctx)
 
-	result, err := tx.Exec(ctx, query, args...)
+	err = checkVersion(ctx, tx, t.ID, t.Version)
+	if err != nil {
+		return err
+	}
+
+	err = updateTask(ctx, tx, t)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// updateTask does the work of Update.
+func updateTask(ctx context.Context, tx pgx.Tx, t *Task) error {
+	query := `UPDATE tasks
+	SET title = $1, description = $2, due_at = $3, remind_at = $4,
+		project_id = COALESCE(NULLIF($6, ''), project_id), recurrence = $7,
+		priority = COALESCE(NULLIF($8::TEXT, '')::task_priority, priority),
+		field_changed_at = CASE WHEN $9 THEN field_changed_at || jsonb_build_object('tags', now()) ELSE field_changed_at END
+	WHERE id = $5 AND completed = false AND deleted_at IS NULL
+	RETURNING version`
+
+	args := []any{t.Title, t.Description, t.DueAt, t.RemindAt, t.ID, t.ProjectID, t.Recurrence, t.Priority, t.Tags != nil}
+
+	if t.ProjectID != "" {
+		err := checkProject(ctx, tx, t.ProjectID, t.UserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	before, err := snapshotTask(ctx, tx, t.ID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, query, args...).Scan(&t.Version)
 	if err != nil {
 		switch {
-		case strings.Contains(db.FormatErr(err), "tasks_title_user_id_key"):
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrOpFailed
+		case strings.Contains(db.FormatErr(err), "tasks_title_project_id_key"):
 			return ErrDuplicateTask
 		default:
 			return err
 		}
 	}
 
-	if result.RowsAffected() != 1 {
-		return ErrOpFailed
+	if t.ProjectID != "" {
+		err = moveSubtasks(ctx, tx, t.ID, t.UserID, t.ProjectID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if t.Tags != nil {
+		err = setTaskTags(ctx, tx, t.ID, t.UserID, t.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	return recordTaskEvent(ctx, tx, t.ID, t.UserID, TaskUpdated, before)
+}
+
+// Complete marks an open task as completed, moving it to the done status of the
+// user's workflow. It fails with ErrOpenBlockers while a task blocking it is still open.
+// When the task has open subtasks it either fails with ErrOpenSubtasks or, if
+// cascade is set, completes them too. Completing a recurring task creates its
+// next occurrence in the same transaction. It fails with ErrEditConflict unless
+// the task is still at version, a zero version skipping the check.
+func (m *TasksModel) Complete(ctx context.Context, id, userID string, version int64, cascade bool) error {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = checkVersion(ctx, tx, id, version)
+	if err != nil {
+		return err
+	}
+
+	err = completeTask(ctx, tx, id, userID, cascade)
+	if err != nil {
+		return err
 	}
 
 	err = tx.Commit(ctx)
@@ -199,13 +616,64 @@ func (m *TasksModel) Update(ctx context.Context, t *Task) error {
 	return nil
 }
 
-func (m *TasksModel) Complete(ctx context.Context, id, userID string) error {
+// completeTask does the work of Complete.
+func completeTask(ctx context.Context, tx pgx.Tx, id, userID string, cascade bool) error {
 	query := `UPDATE tasks
-	SET completed = true
-	WHERE id = $1 AND user_id = $2 AND completed = false`
+	SET completed = true, completed_at = now(), status = ` + doneStatus + `
+	WHERE id = $1 AND user_id = $2 AND completed = false AND deleted_at IS NULL`
 
-	args := []any{id, userID}
+	before, err := snapshotTask(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrOpFailed
+	}
+
+	blocked, err := hasOpenBlockers(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if blocked {
+		return ErrOpenBlockers
+	}
+
+	if cascade {
+		err = completeSubtasks(ctx, tx, id, userID)
+		if err != nil {
+			return err
+		}
+	} else {
+		open, serr := hasOpenSubtasks(ctx, tx, id)
+		if serr != nil {
+			return serr
+		}
+
+		if open {
+			return ErrOpenSubtasks
+		}
+	}
+
+	err = recordTaskEvent(ctx, tx, id, userID, TaskCompleted, before)
+	if err != nil {
+		return err
+	}
+
+	return createNextOccurrence(ctx, tx, id, userID)
+}
 
+// Reopen marks a completed task as open again in the first status of the
+// user's workflow, together with any completed ancestor, taking them out of
+// the archive. It fails with ErrDuplicateTask when an open task in the
+// project already has the title.
+func (m *TasksModel) Reopen(ctx context.Context, id, userID string) error {
 	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
 		IsoLevel:       pgx.Serializable,
 		AccessMode:     pgx.ReadWrite,
@@ -217,15 +685,11 @@ func (m *TasksModel) Complete(ctx context.Context, id, userID string) error {
 
 	defer tx.Rollback(ctx)
 
-	result, err := tx.Exec(ctx, query, args...)
+	err = reopenTask(ctx, tx, id, userID, "")
 	if err != nil {
 		return err
 	}
 
-	if result.RowsAffected() != 1 {
-		return ErrOpFailed
-	}
-
 	err = tx.Commit(ctx)
 	if err != nil {
 		return err
@@ -234,12 +698,44 @@ func (m *TasksModel) Complete(ctx context.Context, id, userID string) error {
 	return nil
 }
 
-func (m *TasksModel) Delete(ctx context.Context, id, userID string) error {
-	query := `DELETE FROM tasks
-	WHERE id = $1 AND user_id = $2`
+// reopenTask moves a completed task to status, or to the first open status
+// when status is empty.
+func reopenTask(ctx context.Context, tx pgx.Tx, id, userID, status string) error {
+	query := `UPDATE tasks
+	SET completed = false, completed_at = NULL, archived_at = NULL, status = COALESCE(NULLIF($3, ''), ` + initialStatus + `)
+	WHERE id = $1 AND user_id = $2 AND completed = true AND deleted_at IS NULL`
 
-	args := []any{id, userID}
+	before, err := snapshotTask(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, query, id, userID, status)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "tasks_title_project_id_key"):
+			return ErrDuplicateTask
+		default:
+			return err
+		}
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrOpFailed
+	}
 
+	err = recordTaskEvent(ctx, tx, id, userID, TaskUpdated, before)
+	if err != nil {
+		return err
+	}
+
+	return reopenAncestors(ctx, tx, id, userID)
+}
+
+// Delete moves a task and its subtasks to the trash, from where they can be
+// restored until they are purged. It fails with ErrEditConflict unless the
+// task is still at version, a zero version skipping the check.
+func (m *TasksModel) Delete(ctx context.Context, id, userID string, version int64) error {
 	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
 		IsoLevel:       pgx.Serializable,
 		AccessMode:     pgx.ReadWrite,
@@ -251,13 +747,14 @@ func (m *TasksModel) Delete(ctx context.Context, id, userID string) error {
 
 	defer tx.Rollback(ctx)
 
-	result, err := tx.Exec(ctx, query, args...)
+	err = checkVersion(ctx, tx, id, version)
 	if err != nil {
 		return err
 	}
 
-	if result.RowsAffected() != 1 {
-		return ErrOpFailed
+	err = deleteTask(ctx, tx, id, userID)
+	if err != nil {
+		return err
 	}
 
 	err = tx.Commit(ctx)
@@ -267,3 +764,65 @@ func (m *TasksModel) Delete(ctx context.Context, id, userID string) error {
 
 	return nil
 }
+
+// deleteTask does the work of Delete.
+func deleteTask(ctx context.Context, tx pgx.Tx, id, userID string) error {
+	query := `WITH RECURSIVE subtree AS (
+		SELECT id FROM tasks WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		UNION ALL
+		SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+		WHERE t.deleted_at IS NULL
+	)
+	SELECT ARRAY(SELECT id FROM subtree ORDER BY id)`
+
+	var ids []string
+
+	err := tx.QueryRow(ctx, query, id, userID).Scan(&ids)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return ErrOpFailed
+	}
+
+	before, err := snapshotTasks(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE tasks
+	SET deleted_at = now()
+	WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return err
+	}
+
+	return recordTaskEvents(ctx, tx, ids, userID, TaskDeleted, before)
+}
+
+// checkVersion fails with ErrEditConflict when a task is no longer at version,
+// and with ErrOpFailed when it does not exist. A zero version matches any.
+func checkVersion(ctx context.Context, tx pgx.Tx, id string, version int64) error {
+	if version == 0 {
+		return nil
+	}
+
+	var current int64
+
+	err := tx.QueryRow(ctx, `SELECT version FROM tasks WHERE id = $1 AND deleted_at IS NULL`, id).Scan(&current)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrOpFailed
+		default:
+			return err
+		}
+	}
+
+	if current != version {
+		return ErrEditConflict
+	}
+
+	return nil
+}
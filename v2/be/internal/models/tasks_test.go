@@ -3,6 +3,7 @@ package models_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"v2/be/internal/db"
 	"v2/be/internal/models"
@@ -126,9 +127,10 @@ func TestTasksAll(t *testing.T) {
 			require.NoError(t, err)
 		}
 
-		ts, err := tasks.All(context.Background(), u.ID)
+		ts, next, err := tasks.All(context.Background(), u.ID, models.TaskFilter{})
 		require.NoError(t, err)
 		require.Len(t, ts, 3)
+		require.Empty(t, next)
 
 		for _, tt := range ts {
 			require.NotEmpty(t, tt.ID)
@@ -138,6 +140,127 @@ func TestTasksAll(t *testing.T) {
 		}
 	})
 
+	t.Run("paginated", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+
+		users := &models.UsersModel{Pool: pool}
+		u := &models.User{
+			ID:       db.NewID(),
+			Username: gofakeit.Username(),
+			Password: []byte(testUserPassword(t)),
+		}
+
+		err := users.Create(context.Background(), u)
+		require.NoError(t, err)
+
+		tasks := &models.TasksModel{Pool: pool}
+		titles := []string{"a", "c", "b", "e", "d"}
+		for _, title := range titles {
+			task := &models.Task{
+				ID:          db.NewID(),
+				UserID:      u.ID,
+				Title:       title,
+				Description: gofakeit.Word(),
+			}
+			err = tasks.Create(context.Background(), task)
+			require.NoError(t, err)
+		}
+
+		f := models.TaskFilter{Sort: "title", Limit: 2}
+
+		var got []string
+		for {
+			ts, next, err := tasks.All(context.Background(), u.ID, f)
+			require.NoError(t, err)
+
+			for _, tt := range ts {
+				got = append(got, tt.Title)
+			}
+
+			if next == "" {
+				break
+			}
+
+			f.Cursor, err = models.DecodeCursor(next, f.Sort)
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, []string{"a", "b", "c", "d", "e"}, got)
+
+		ts, _, err := tasks.All(context.Background(), u.ID, models.TaskFilter{Sort: "-created", Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, ts, 1)
+		require.Equal(t, "d", ts[0].Title)
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+
+		users := &models.UsersModel{Pool: pool}
+		u := &models.User{
+			ID:       db.NewID(),
+			Username: gofakeit.Username(),
+			Password: []byte(testUserPassword(t)),
+		}
+
+		err := users.Create(context.Background(), u)
+		require.NoError(t, err)
+
+		tasks := &models.TasksModel{Pool: pool}
+		for i, title := range []string{"write report", "read 100% of book", "report bug"} {
+			task := &models.Task{
+				ID:          db.NewID(),
+				UserID:      u.ID,
+				Title:       title,
+				Description: gofakeit.Word(),
+				Completed:   i == 2,
+			}
+			err = tasks.Create(context.Background(), task)
+			require.NoError(t, err)
+		}
+
+		completed := false
+
+		tests := []struct {
+			name   string
+			filter models.TaskFilter
+			expect int
+		}{
+			{
+				name:   "query",
+				filter: models.TaskFilter{Query: "report"},
+				expect: 2,
+			},
+			{
+				name:   "query with wildcard",
+				filter: models.TaskFilter{Query: "100%"},
+				expect: 1,
+			},
+			{
+				name:   "open only",
+				filter: models.TaskFilter{Completed: &completed},
+				expect: 2,
+			},
+			{
+				name:   "open with query",
+				filter: models.TaskFilter{Completed: &completed, Query: "report"},
+				expect: 1,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ts, _, err := tasks.All(context.Background(), u.ID, tt.filter)
+				require.NoError(t, err)
+				require.Len(t, ts, tt.expect)
+			})
+		}
+	})
+
 	t.Run("empty", func(t *testing.T) {
 		t.Parallel()
 
@@ -171,7 +294,7 @@ func TestTasksAll(t *testing.T) {
 
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
-				ts, err := tasks.All(context.Background(), tt.id)
+				ts, _, err := tasks.All(context.Background(), tt.id, models.TaskFilter{})
 				require.NoError(t, err)
 				require.Empty(t, ts)
 			})
@@ -188,12 +311,71 @@ func TestTasksAll(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
 
-		ts, err := tasks.All(ctx, db.NewID())
+		ts, _, err := tasks.All(ctx, db.NewID(), models.TaskFilter{})
 		require.Error(t, err)
 		require.Empty(t, ts)
 	})
 }
 
+func TestTasksSearch(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+
+		users := &models.UsersModel{Pool: pool}
+		u := &models.User{
+			ID:       db.NewID(),
+			Username: gofakeit.Username(),
+			Password: []byte(testUserPassword(t)),
+		}
+
+		err := users.Create(context.Background(), u)
+		require.NoError(t, err)
+
+		tasks := &models.TasksModel{Pool: pool}
+		for _, tt := range []struct{ title, description string }{
+			{"deploy release", "ship the new billing service"},
+			{"weekly report", "summarise the release notes"},
+			{"water plants", "kitchen and balcony"},
+		} {
+			task := &models.Task{
+				ID:          db.NewID(),
+				UserID:      u.ID,
+				Title:       tt.title,
+				Description: tt.description,
+			}
+			err = tasks.Create(context.Background(), task)
+			require.NoError(t, err)
+		}
+
+		ms, err := tasks.Search(context.Background(), u.ID, "release", 10)
+		require.NoError(t, err)
+		require.Len(t, ms, 2)
+		require.Equal(t, "deploy release", ms[0].Title)
+		require.Contains(t, ms[0].Snippet, "<mark>")
+
+		ms, err = tasks.Search(context.Background(), db.NewID(), "release", 10)
+		require.NoError(t, err)
+		require.Empty(t, ms)
+	})
+
+	t.Run("cancelled ctx", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+
+		tasks := &models.TasksModel{Pool: pool}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		ms, err := tasks.Search(ctx, db.NewID(), "release", 10)
+		require.Error(t, err)
+		require.Empty(t, ms)
+	})
+}
+
 func TestTasksGetByID(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
 		t.Parallel()
@@ -283,6 +465,46 @@ func TestTasksGetByID(t *testing.T) {
 		}
 	})
 
+	t.Run("due dates", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+
+		users := &models.UsersModel{Pool: pool}
+		u := &models.User{
+			ID:       db.NewID(),
+			Username: gofakeit.Username(),
+			Password: []byte(testUserPassword(t)),
+		}
+
+		err := users.Create(context.Background(), u)
+		require.NoError(t, err)
+
+		due := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+		remind := due.Add(-time.Hour)
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := &models.Task{
+			ID:          db.NewID(),
+			UserID:      u.ID,
+			Title:       gofakeit.Verb(),
+			Description: gofakeit.Blurb(),
+			DueAt:       &due,
+			RemindAt:    &remind,
+		}
+
+		err = tasks.Create(context.Background(), task)
+		require.NoError(t, err)
+
+		rt, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.NotNil(t, rt.DueAt)
+		require.NotNil(t, rt.RemindAt)
+		require.True(t, due.Equal(*rt.DueAt))
+		require.True(t, remind.Equal(*rt.RemindAt))
+		require.True(t, rt.Overdue)
+	})
+
 	t.Run("cancelled ctx", func(t *testing.T) {
 		t.Parallel()
 
@@ -299,6 +521,74 @@ func TestTasksGetByID(t *testing.T) {
 	})
 }
 
+func TestTaskIsOverdue(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	tests := []struct {
+		name   string
+		task   models.Task
+		expect bool
+	}{
+		{
+			name:   "no due date",
+			task:   models.Task{},
+			expect: false,
+		},
+		{
+			name:   "due in future",
+			task:   models.Task{DueAt: &future},
+			expect: false,
+		},
+		{
+			name:   "due in past",
+			task:   models.Task{DueAt: &past},
+			expect: true,
+		},
+		{
+			name:   "completed",
+			task:   models.Task{DueAt: &past, Completed: true},
+			expect: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expect, tt.task.IsOverdue(now))
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor *models.Cursor
+		sort   string
+		err    error
+	}{
+		{name: "same sort", cursor: &models.Cursor{Sort: "title", Key: "read", ID: db.NewID()}, sort: "title"},
+		{name: "priority", cursor: &models.Cursor{Sort: "-priority", Key: "high", ID: db.NewID()}, sort: "-priority"},
+		{name: "other direction", cursor: &models.Cursor{Sort: "title", Key: "read", ID: db.NewID()}, sort: "-title", err: models.ErrInvalidCursor},
+		{name: "other sort", cursor: &models.Cursor{Sort: "title", Key: "read", ID: db.NewID()}, sort: "priority", err: models.ErrInvalidCursor},
+		{name: "unknown priority", cursor: &models.Cursor{Sort: "priority", Key: "soon", ID: db.NewID()}, sort: "priority", err: models.ErrInvalidCursor},
+		{name: "no id", cursor: &models.Cursor{Sort: "title", Key: "read"}, sort: "title", err: models.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := models.DecodeCursor(tt.cursor.Encode(), tt.sort)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.cursor, c)
+		})
+	}
+}
+
 func TestTasksUpdate(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
 		t.Parallel()
@@ -442,6 +732,46 @@ func TestTasksUpdate(t *testing.T) {
 		require.ErrorIs(t, err, models.ErrDuplicateTask)
 	})
 
+	t.Run("moves subtasks", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		projects := &models.ProjectsModel{Pool: pool}
+		work := &models.Project{ID: db.NewID(), UserID: u.ID, Name: "work"}
+		require.NoError(t, projects.Create(context.Background(), work))
+
+		tasks := &models.TasksModel{Pool: pool}
+		parent := testSubtask(t, tasks, u.ID, nil)
+		child := testSubtask(t, tasks, u.ID, &parent.ID)
+		grandchild := testSubtask(t, tasks, u.ID, &child.ID)
+
+		taken := &models.Task{ID: db.NewID(), UserID: u.ID, ProjectID: work.ID, Title: grandchild.Title, Description: gofakeit.Phrase()}
+		require.NoError(t, tasks.Create(context.Background(), taken))
+
+		moved := *parent
+		moved.ProjectID = work.ID
+
+		err := tasks.Update(context.Background(), &moved)
+		require.ErrorIs(t, err, models.ErrDuplicateTask)
+
+		require.NoError(t, tasks.Delete(context.Background(), taken.ID, u.ID, 0))
+		require.NoError(t, tasks.Update(context.Background(), &moved))
+
+		for _, id := range []string{parent.ID, child.ID, grandchild.ID} {
+			rt, err := tasks.GetByID(context.Background(), id, u.ID)
+			require.NoError(t, err)
+			require.Equal(t, work.ID, rt.ProjectID)
+		}
+
+		events, err := tasks.History(context.Background(), grandchild.ID, u.ID)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		require.Equal(t, models.TaskUpdated, events[1].Action)
+		require.Equal(t, work.ID, events[1].Changes["project_id"].New)
+	})
+
 	t.Run("cancelled ctx", func(t *testing.T) {
 		t.Parallel()
 
@@ -491,12 +821,13 @@ func TestTasksComplete(t *testing.T) {
 		err = tasks.Create(context.Background(), task)
 		require.NoError(t, err)
 
-		err = tasks.Complete(context.Background(), task.ID, u.ID)
+		err = tasks.Complete(context.Background(), task.ID, u.ID, 0, false)
 		require.NoError(t, err)
 
 		ct, err := tasks.GetByID(context.Background(), task.ID, u.ID)
 		require.NoError(t, err)
 		require.True(t, ct.Completed)
+		require.NotNil(t, ct.CompletedAt)
 	})
 
 	t.Run("completed task", func(t *testing.T) {
@@ -526,7 +857,7 @@ func TestTasksComplete(t *testing.T) {
 		err = tasks.Create(context.Background(), task)
 		require.NoError(t, err)
 
-		err = tasks.Complete(context.Background(), task.ID, u.ID)
+		err = tasks.Complete(context.Background(), task.ID, u.ID, 0, false)
 		require.ErrorIs(t, err, models.ErrOpFailed)
 	})
 
@@ -575,7 +906,7 @@ func TestTasksComplete(t *testing.T) {
 
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
-				err := tasks.Complete(context.Background(), tt.id, tt.userID)
+				err := tasks.Complete(context.Background(), tt.id, tt.userID, 0, false)
 				require.ErrorIs(t, err, models.ErrOpFailed)
 			})
 		}
@@ -591,11 +922,88 @@ func TestTasksComplete(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
 
-		err := tasks.Complete(ctx, db.NewID(), db.NewID())
+		err := tasks.Complete(ctx, db.NewID(), db.NewID(), 0, false)
 		require.Error(t, err)
 	})
 }
 
+func TestTasksReopen(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := &models.Task{
+			ID:          db.NewID(),
+			UserID:      u.ID,
+			Title:       gofakeit.BookTitle(),
+			Description: gofakeit.Phrase(),
+		}
+
+		require.NoError(t, tasks.Create(context.Background(), task))
+		require.NoError(t, tasks.Complete(context.Background(), task.ID, u.ID, 0, false))
+
+		err := tasks.Reopen(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+
+		rt, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.False(t, rt.Completed)
+		require.Nil(t, rt.CompletedAt)
+	})
+
+	t.Run("reopens parent", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		parent := testSubtask(t, tasks, u.ID, nil)
+		child := testSubtask(t, tasks, u.ID, &parent.ID)
+
+		require.NoError(t, tasks.Complete(context.Background(), parent.ID, u.ID, 0, true))
+		require.NoError(t, tasks.Reopen(context.Background(), child.ID, u.ID))
+
+		pt, err := tasks.GetByID(context.Background(), parent.ID, u.ID)
+		require.NoError(t, err)
+		require.False(t, pt.Completed)
+	})
+
+	t.Run("open task", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		err := tasks.Reopen(context.Background(), task.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrOpFailed)
+	})
+
+	t.Run("duplicate open task", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		done := &models.Task{ID: db.NewID(), UserID: u.ID, Title: "water plants", Description: gofakeit.Phrase()}
+		require.NoError(t, tasks.Create(context.Background(), done))
+		require.NoError(t, tasks.Complete(context.Background(), done.ID, u.ID, 0, false))
+
+		again := &models.Task{ID: db.NewID(), UserID: u.ID, Title: "water plants", Description: gofakeit.Phrase()}
+		require.NoError(t, tasks.Create(context.Background(), again))
+
+		err := tasks.Reopen(context.Background(), done.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrDuplicateTask)
+	})
+}
+
 func TestTasksModelDelete(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
 		t.Parallel()
@@ -624,7 +1032,7 @@ func TestTasksModelDelete(t *testing.T) {
 		err = tasks.Create(context.Background(), task)
 		require.NoError(t, err)
 
-		err = tasks.Delete(context.Background(), task.ID, u.ID)
+		err = tasks.Delete(context.Background(), task.ID, u.ID, 0)
 		require.NoError(t, err)
 
 		task, err = tasks.GetByID(context.Background(), task.ID, u.ID)
@@ -678,7 +1086,7 @@ func TestTasksModelDelete(t *testing.T) {
 
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
-				err := tasks.Delete(context.Background(), tt.id, tt.userID)
+				err := tasks.Delete(context.Background(), tt.id, tt.userID, 0)
 				require.ErrorIs(t, err, models.ErrOpFailed)
 			})
 		}
@@ -694,7 +1102,57 @@ func TestTasksModelDelete(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
 
-		err := tasks.Delete(ctx, db.NewID(), db.NewID())
+		err := tasks.Delete(ctx, db.NewID(), db.NewID(), 0)
 		require.Error(t, err)
 	})
 }
+
+func TestTasksVersion(t *testing.T) {
+	t.Run("increments on write", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+		require.EqualValues(t, 1, task.Version)
+
+		task.Title = gofakeit.BookTitle()
+		require.NoError(t, tasks.Update(context.Background(), task))
+		require.EqualValues(t, 2, task.Version)
+
+		require.NoError(t, tasks.Complete(context.Background(), task.ID, u.ID, task.Version, false))
+
+		rt, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, rt.Version)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		first := *task
+		first.Title = gofakeit.BookTitle()
+		require.NoError(t, tasks.Update(context.Background(), &first))
+
+		second := *task
+		second.Title = gofakeit.BookTitle()
+		err := tasks.Update(context.Background(), &second)
+		require.ErrorIs(t, err, models.ErrEditConflict)
+
+		err = tasks.Complete(context.Background(), task.ID, u.ID, task.Version, false)
+		require.ErrorIs(t, err, models.ErrEditConflict)
+
+		err = tasks.Delete(context.Background(), task.ID, u.ID, task.Version)
+		require.ErrorIs(t, err, models.ErrEditConflict)
+
+		require.NoError(t, tasks.Delete(context.Background(), task.ID, u.ID, first.Version))
+	})
+}
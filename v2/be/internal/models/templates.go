@@ -0,0 +1,303 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"v2/be/internal/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrDuplicateTemplate = errors.New("template exists")
+	ErrMissingVariable   = errors.New("template variable has no value")
+)
+
+// MaxTemplateSubtasks is the largest number of subtasks a template may hold
+const MaxTemplateSubtasks = 50
+
+// templateVariable matches the {{name}} placeholders of a template
+var templateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// TemplateSubtask is a subtask created along with every task of a template
+type TemplateSubtask struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// Template holds a repeatable task. Its title, description and subtasks may
+// contain {{name}} placeholders filled in when the template is instantiated.
+type Template struct {
+	ID          string            `json:"id"`
+	UserID      string            `json:"user_id"`
+	Name        string            `json:"name"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Subtasks    []TemplateSubtask `json:"subtasks"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// expand fills in the placeholders of s with vars, failing with
+// ErrMissingVariable on the first placeholder vars has no value for
+func expand(s string, vars map[string]string) (string, error) {
+	var missing string
+
+	out := templateVariable.ReplaceAllStringFunc(s, func(match string) string {
+		name := templateVariable.FindStringSubmatch(match)[1]
+
+		value, ok := vars[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+
+		return value
+	})
+
+	if missing != "" {
+		return "", fmt.Errorf("%w: %s", ErrMissingVariable, missing)
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+type TemplatesModel struct {
+	Pool *pgxpool.Pool
+}
+
+func (m *TemplatesModel) Create(ctx context.Context, t *Template) error {
+	query := `INSERT INTO task_templates (id, user_id, name, title, description, subtasks)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING created_at`
+
+	if t.Subtasks == nil {
+		t.Subtasks = []TemplateSubtask{}
+	}
+
+	args := []any{t.ID, t.UserID, t.Name, t.Title, t.Description, t.Subtasks}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, query, args...).Scan(&t.CreatedAt)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "task_templates_name_user_id_key"):
+			return ErrDuplicateTemplate
+		default:
+			return err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// All returns the user's templates by name
+func (m *TemplatesModel) All(ctx context.Context, userID string) ([]*Template, error) {
+	query := `SELECT id, user_id, name, title, description, subtasks, created_at
+	FROM task_templates
+	WHERE user_id = $1
+	ORDER BY name`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var templates []*Template
+
+	rows, err := tx.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var t Template
+
+		serr := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.Title, &t.Description, &t.Subtasks, &t.CreatedAt)
+		if serr != nil {
+			return nil, serr
+		}
+
+		templates = append(templates, &t)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (m *TemplatesModel) GetByID(ctx context.Context, id, userID string) (*Template, error) {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	t, err := getTemplate(ctx, tx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (m *TemplatesModel) Delete(ctx context.Context, id, userID string) error {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `DELETE FROM task_templates WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrRecordNotFound
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Instantiate creates a task and its subtasks from a template in projectID,
+// or in the user's inbox when projectID is empty, and returns the id of the
+// task. The placeholders are filled in with vars, {{date}} defaulting to
+// today. Either every task is created or none is.
+func (m *TemplatesModel) Instantiate(ctx context.Context, id, userID, projectID string, vars map[string]string) (string, error) {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	defer tx.Rollback(ctx)
+
+	tmpl, err := getTemplate(ctx, tx, id, userID)
+	if err != nil {
+		return "", err
+	}
+
+	values := map[string]string{"date": time.Now().Format(time.DateOnly)}
+	for k, v := range vars {
+		values[k] = v
+	}
+
+	t := &Task{ID: db.NewID(), UserID: userID, ProjectID: projectID}
+
+	t.Title, err = expand(tmpl.Title, values)
+	if err != nil {
+		return "", err
+	}
+
+	t.Description, err = expand(tmpl.Description, values)
+	if err != nil {
+		return "", err
+	}
+
+	err = createTask(ctx, tx, t)
+	if err != nil {
+		return "", err
+	}
+
+	for _, s := range tmpl.Subtasks {
+		sub := &Task{ID: db.NewID(), UserID: userID, ParentID: &t.ID}
+
+		sub.Title, err = expand(s.Title, values)
+		if err != nil {
+			return "", err
+		}
+
+		sub.Description, err = expand(s.Description, values)
+		if err != nil {
+			return "", err
+		}
+
+		err = createTask(ctx, tx, sub)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return t.ID, nil
+}
+
+// getTemplate returns a template of the user.
+func getTemplate(ctx context.Context, tx pgx.Tx, id, userID string) (*Template, error) {
+	var t Template
+
+	err := tx.QueryRow(ctx, `SELECT id, user_id, name, title, description, subtasks, created_at
+	FROM task_templates
+	WHERE id = $1 AND user_id = $2`, id, userID).Scan(&t.ID, &t.UserID, &t.Name, &t.Title, &t.Description, &t.Subtasks, &t.CreatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &t, nil
+}
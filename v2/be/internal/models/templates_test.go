@@ -0,0 +1,115 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplates(t *testing.T) {
+	t.Run("create and delete", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		templates := &models.TemplatesModel{Pool: pool}
+
+		tmpl := &models.Template{ID: db.NewID(), UserID: u.ID, Name: "review", Title: "Review {{date}}", Description: "weekly"}
+		require.NoError(t, templates.Create(context.Background(), tmpl))
+		require.False(t, tmpl.CreatedAt.IsZero())
+
+		dup := &models.Template{ID: db.NewID(), UserID: u.ID, Name: "review", Title: "Review", Description: "weekly"}
+		require.ErrorIs(t, templates.Create(context.Background(), dup), models.ErrDuplicateTemplate)
+
+		all, err := templates.All(context.Background(), u.ID)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Empty(t, all[0].Subtasks)
+
+		require.NoError(t, templates.Delete(context.Background(), tmpl.ID, u.ID))
+
+		_, err = templates.GetByID(context.Background(), tmpl.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+
+	t.Run("instantiate", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		templates := &models.TemplatesModel{Pool: pool}
+
+		tmpl := &models.Template{
+			ID:          db.NewID(),
+			UserID:      u.ID,
+			Name:        "release",
+			Title:       "Release {{version}} on {{ date }}",
+			Description: "Ship {{version}}",
+			Subtasks: []models.TemplateSubtask{
+				{Title: "Tag {{version}}", Description: "git tag"},
+				{Title: "Announce {{version}}", Description: "post the notes"},
+			},
+		}
+		require.NoError(t, templates.Create(context.Background(), tmpl))
+
+		_, err := templates.Instantiate(context.Background(), tmpl.ID, u.ID, "", nil)
+		require.ErrorIs(t, err, models.ErrMissingVariable)
+
+		id, err := templates.Instantiate(context.Background(), tmpl.ID, u.ID, "", map[string]string{"version": "1.2.0"})
+		require.NoError(t, err)
+
+		tasks := &models.TasksModel{Pool: pool}
+
+		task, err := tasks.GetByID(context.Background(), id, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Release 1.2.0 on "+time.Now().Format(time.DateOnly), task.Title)
+		require.Equal(t, "Ship 1.2.0", task.Description)
+
+		children, err := tasks.Children(context.Background(), id, u.ID)
+		require.NoError(t, err)
+		require.Len(t, children, 2)
+		require.Equal(t, "Tag 1.2.0", children[0].Title)
+
+		_, err = templates.Instantiate(context.Background(), tmpl.ID, u.ID, "", map[string]string{"version": "1.2.0"})
+		require.ErrorIs(t, err, models.ErrDuplicateTask)
+
+		_, err = templates.Instantiate(context.Background(), db.NewID(), u.ID, "", nil)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+	})
+
+	t.Run("instantiate rolls back", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		templates := &models.TemplatesModel{Pool: pool}
+
+		tmpl := &models.Template{
+			ID:          db.NewID(),
+			UserID:      u.ID,
+			Name:        "onboarding",
+			Title:       "Onboard {{name}}",
+			Description: "welcome",
+			Subtasks: []models.TemplateSubtask{
+				{Title: "Laptop", Description: "order"},
+				{Title: "Laptop", Description: "order again"},
+			},
+		}
+		require.NoError(t, templates.Create(context.Background(), tmpl))
+
+		_, err := templates.Instantiate(context.Background(), tmpl.ID, u.ID, "", map[string]string{"name": "ada"})
+		require.ErrorIs(t, err, models.ErrDuplicateTask)
+
+		all, _, err := (&models.TasksModel{Pool: pool}).All(context.Background(), u.ID, models.TaskFilter{})
+		require.NoError(t, err)
+		require.Empty(t, all)
+	})
+}
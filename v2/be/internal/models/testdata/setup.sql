@@ -12,11 +12,276 @@ CREATE TABLE IF NOT EXISTS sessions (
 
 CREATE INDEX sessions_expiry_idx ON sessions (expiry);
 
+CREATE TABLE IF NOT EXISTS projects (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (name <> ''),
+    inbox BOOLEAN NOT NULL DEFAULT false,
+    UNIQUE(name, user_id)
+);
+
+CREATE UNIQUE INDEX projects_inbox_idx ON projects (user_id) WHERE inbox;
+
+CREATE TABLE IF NOT EXISTS statuses (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (name <> ''),
+    position INTEGER NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT false,
+    UNIQUE(name, user_id)
+);
+
+CREATE UNIQUE INDEX statuses_done_idx ON statuses (user_id) WHERE done;
+
+CREATE TABLE IF NOT EXISTS status_transitions (
+    user_id TEXT NOT NULL,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    PRIMARY KEY (user_id, from_status, to_status),
+    CONSTRAINT status_transitions_from_status_fkey FOREIGN KEY (from_status, user_id)
+        REFERENCES statuses (name, user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT status_transitions_to_status_fkey FOREIGN KEY (to_status, user_id)
+        REFERENCES statuses (name, user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TYPE workspace_role AS ENUM ('guest', 'member', 'admin', 'owner');
+
+CREATE TABLE IF NOT EXISTS workspaces (
+    id TEXT PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL CHECK (name <> ''),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id TEXT NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role workspace_role NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT workspace_members_pkey PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE UNIQUE INDEX workspace_members_owner_key ON workspace_members (workspace_id) WHERE role = 'owner';
+
+CREATE INDEX workspace_members_user_id_idx ON workspace_members (user_id);
+
+CREATE TYPE task_priority AS ENUM ('none', 'low', 'medium', 'high', 'urgent');
+
 CREATE TABLE IF NOT EXISTS tasks (
     id TEXT PRIMARY KEY NOT NULL,
     user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    project_id TEXT NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    parent_id TEXT REFERENCES tasks (id) ON DELETE CASCADE,
+    assignee_id TEXT REFERENCES users (id) ON DELETE SET NULL,
+    workspace_id TEXT REFERENCES workspaces (id) ON DELETE SET NULL,
     title TEXT NOT NULL CHECK (title <> ''),
     description TEXT NOT NULL CHECK (description <> ''),
+    status TEXT NOT NULL,
     completed BOOLEAN NOT NULL DEFAULT false,
-    UNIQUE(title, user_id)
+    completed_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    archived_at TIMESTAMPTZ,
+    due_at TIMESTAMPTZ,
+    remind_at TIMESTAMPTZ,
+    recurrence TEXT CHECK (recurrence <> ''),
+    priority task_priority NOT NULL DEFAULT 'none',
+    position TEXT COLLATE "C" NOT NULL,
+    version BIGINT NOT NULL DEFAULT 1,
+    changed_xid XID8 NOT NULL DEFAULT pg_current_xact_id(),
+    field_changed_at JSONB NOT NULL DEFAULT '{}',
+    search TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', title), 'A') ||
+        setweight(to_tsvector('english', description), 'B')
+    ) STORED,
+    CONSTRAINT tasks_remind_at_check CHECK (remind_at IS NULL OR due_at IS NULL OR remind_at <= due_at),
+    CONSTRAINT tasks_completed_at_check CHECK (completed_at IS NULL OR completed),
+    CONSTRAINT tasks_archived_at_check CHECK (archived_at IS NULL OR completed),
+    CONSTRAINT tasks_status_fkey FOREIGN KEY (status, user_id) REFERENCES statuses (name, user_id) ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX tasks_title_project_id_key ON tasks (title, project_id) WHERE NOT completed AND deleted_at IS NULL;
+
+CREATE INDEX tasks_deleted_at_idx ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX tasks_search_idx ON tasks USING GIN (search);
+
+CREATE INDEX tasks_parent_id_idx ON tasks (parent_id);
+
+CREATE INDEX tasks_user_id_position_idx ON tasks (user_id, position, id);
+
+CREATE INDEX tasks_assignee_id_idx ON tasks (assignee_id) WHERE assignee_id IS NOT NULL;
+
+CREATE INDEX tasks_workspace_id_idx ON tasks (workspace_id) WHERE workspace_id IS NOT NULL;
+
+CREATE INDEX tasks_user_id_archived_at_idx ON tasks (user_id, archived_at) WHERE archived_at IS NOT NULL;
+
+CREATE INDEX tasks_user_id_changed_xid_idx ON tasks (user_id, changed_xid);
+
+CREATE FUNCTION tasks_next_version() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tasks_version_trigger
+    BEFORE UPDATE ON tasks
+    FOR EACH ROW
+    EXECUTE FUNCTION tasks_next_version();
+
+CREATE FUNCTION tasks_track_changes() RETURNS TRIGGER AS $$
+DECLARE
+    field TEXT;
+    old_task JSONB;
+    new_task JSONB;
+BEGIN
+    NEW.changed_xid := pg_current_xact_id();
+
+    IF TG_OP = 'UPDATE' THEN
+        old_task := to_jsonb(OLD);
+        new_task := to_jsonb(NEW);
+
+        FOREACH field IN ARRAY ARRAY['project_id', 'parent_id', 'assignee_id', 'title', 'description', 'status',
+            'completed', 'archived_at', 'deleted_at', 'due_at', 'remind_at', 'recurrence', 'priority', 'position'] LOOP
+            IF old_task -> field IS DISTINCT FROM new_task -> field THEN
+                NEW.field_changed_at := NEW.field_changed_at || jsonb_build_object(field, now());
+            END IF;
+        END LOOP;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tasks_changes_trigger
+    BEFORE INSERT OR UPDATE ON tasks
+    FOR EACH ROW
+    EXECUTE FUNCTION tasks_track_changes();
+
+CREATE TABLE IF NOT EXISTS task_tombstones (
+    task_id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    changed_xid XID8 NOT NULL DEFAULT pg_current_xact_id()
+);
+
+CREATE INDEX task_tombstones_user_id_changed_xid_idx ON task_tombstones (user_id, changed_xid);
+
+CREATE FUNCTION tasks_record_tombstone() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO task_tombstones (task_id, user_id)
+    VALUES (OLD.id, OLD.user_id)
+    ON CONFLICT (task_id) DO NOTHING;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tasks_tombstone_trigger
+    AFTER DELETE ON tasks
+    FOR EACH ROW
+    EXECUTE FUNCTION tasks_record_tombstone();
+
+CREATE TABLE IF NOT EXISTS tags (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (name <> ''),
+    UNIQUE(name, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS task_tags (
+    task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    tag_id TEXT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX task_tags_tag_id_idx ON task_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    blocker_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, blocker_id),
+    CHECK (task_id <> blocker_id)
+);
+
+CREATE INDEX task_dependencies_blocker_id_idx ON task_dependencies (blocker_id);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id TEXT PRIMARY KEY NOT NULL,
+    task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    body TEXT NOT NULL CHECK (body <> ''),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
+
+CREATE INDEX comments_task_id_idx ON comments (task_id, id);
+
+CREATE TABLE IF NOT EXISTS task_events (
+    id TEXT PRIMARY KEY NOT NULL,
+    task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    action TEXT NOT NULL CHECK (action IN ('created', 'updated', 'completed', 'deleted')),
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX task_events_task_id_idx ON task_events (task_id, id);
+
+CREATE TABLE IF NOT EXISTS attachments (
+    id TEXT PRIMARY KEY NOT NULL,
+    task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (name <> ''),
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX attachments_task_id_idx ON attachments (task_id, id);
+
+CREATE INDEX attachments_user_id_idx ON attachments (user_id);
+
+CREATE TYPE share_role AS ENUM ('viewer', 'editor');
+
+CREATE TABLE IF NOT EXISTS shares (
+    id TEXT PRIMARY KEY NOT NULL,
+    owner_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    task_id TEXT REFERENCES tasks (id) ON DELETE CASCADE,
+    project_id TEXT REFERENCES projects (id) ON DELETE CASCADE,
+    role share_role NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT shares_target_check CHECK ((task_id IS NULL) <> (project_id IS NULL)),
+    CONSTRAINT shares_owner_id_check CHECK (owner_id <> user_id),
+    CONSTRAINT shares_task_id_user_id_key UNIQUE (task_id, user_id),
+    CONSTRAINT shares_project_id_user_id_key UNIQUE (project_id, user_id)
+);
+
+CREATE INDEX shares_user_id_idx ON shares (user_id);
+
+CREATE INDEX shares_owner_id_idx ON shares (owner_id);
+
+CREATE TABLE IF NOT EXISTS task_templates (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (name <> ''),
+    title TEXT NOT NULL CHECK (title <> ''),
+    description TEXT NOT NULL CHECK (description <> ''),
+    subtasks JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT task_templates_name_user_id_key UNIQUE (name, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id TEXT NOT NULL,
+    key TEXT NOT NULL CHECK (key <> ''),
+    fingerprint BYTEA NOT NULL,
+    status INTEGER,
+    content_type TEXT NOT NULL DEFAULT '',
+    body BYTEA NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -1,5 +1,99 @@
+DROP INDEX idempotency_keys_expires_at_idx;
+
+DROP TABLE idempotency_keys;
+
+DROP TABLE task_templates;
+
+DROP INDEX shares_owner_id_idx;
+
+DROP INDEX shares_user_id_idx;
+
+DROP TABLE shares;
+
+DROP TYPE share_role;
+
+DROP INDEX attachments_user_id_idx;
+
+DROP INDEX attachments_task_id_idx;
+
+DROP TABLE attachments;
+
+DROP INDEX task_events_task_id_idx;
+
+DROP TABLE task_events;
+
+DROP INDEX comments_task_id_idx;
+
+DROP TABLE comments;
+
+DROP INDEX task_dependencies_blocker_id_idx;
+
+DROP TABLE task_dependencies;
+
+DROP INDEX task_tags_tag_id_idx;
+
+DROP TABLE task_tags;
+
+DROP TABLE tags;
+
+DROP TRIGGER tasks_tombstone_trigger ON tasks;
+
+DROP FUNCTION tasks_record_tombstone();
+
+DROP INDEX task_tombstones_user_id_changed_xid_idx;
+
+DROP TABLE task_tombstones;
+
+DROP TRIGGER tasks_changes_trigger ON tasks;
+
+DROP FUNCTION tasks_track_changes();
+
+DROP TRIGGER tasks_version_trigger ON tasks;
+
+DROP FUNCTION tasks_next_version();
+
+DROP INDEX tasks_user_id_changed_xid_idx;
+
+DROP INDEX tasks_user_id_archived_at_idx;
+
+DROP INDEX tasks_workspace_id_idx;
+
+DROP INDEX tasks_assignee_id_idx;
+
+DROP INDEX tasks_user_id_position_idx;
+
+DROP INDEX tasks_parent_id_idx;
+
+DROP INDEX tasks_search_idx;
+
+DROP INDEX tasks_deleted_at_idx;
+
+DROP INDEX tasks_title_project_id_key;
+
 DROP TABLE tasks;
 
+DROP TYPE task_priority;
+
+DROP INDEX workspace_members_user_id_idx;
+
+DROP INDEX workspace_members_owner_key;
+
+DROP TABLE workspace_members;
+
+DROP TABLE workspaces;
+
+DROP TYPE workspace_role;
+
+DROP TABLE status_transitions;
+
+DROP INDEX statuses_done_idx;
+
+DROP TABLE statuses;
+
+DROP INDEX projects_inbox_idx;
+
+DROP TABLE projects;
+
 DROP INDEX sessions_expiry_idx;
 
 DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,200 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"v2/be/internal/db"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultTrashRetention is how long trashed tasks are kept before they are purged
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// Trash returns the user's trashed tasks, most recently deleted first. Subtasks
+// trashed along with their parent are left out as they are restored with it.
+func (m *TasksModel) Trash(ctx context.Context, userID string) ([]*Task, error) {
+	query := `SELECT ` + taskColumns + `
+	FROM tasks
+	WHERE user_id = $1 AND deleted_at IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM tasks p WHERE p.id = tasks.parent_id AND p.deleted_at IS NOT NULL)
+	ORDER BY deleted_at DESC, id`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var tasks []*Task
+
+	rows, err := tx.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		t, terr := scanTask(rows)
+		if terr != nil {
+			return nil, terr
+		}
+
+		tasks = append(tasks, t)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// Restore takes a trashed task out of the trash together with the subtasks
+// deleted along with it, recording the change in their history. Subtasks of a
+// task that is still trashed cannot be restored on their own.
+func (m *TasksModel) Restore(ctx context.Context, id, userID string) error {
+	query := `WITH RECURSIVE subtree AS (
+		SELECT t.id, t.deleted_at
+		FROM tasks t LEFT JOIN tasks p ON p.id = t.parent_id
+		WHERE t.id = $1 AND t.user_id = $2 AND t.deleted_at IS NOT NULL AND p.deleted_at IS NULL
+		UNION ALL
+		SELECT c.id, c.deleted_at
+		FROM tasks c JOIN subtree s ON c.parent_id = s.id
+		WHERE c.deleted_at = s.deleted_at
+	)
+	SELECT ARRAY(SELECT id FROM subtree ORDER BY id)`
+
+	args := []any{id, userID}
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var ids []string
+
+	err = tx.QueryRow(ctx, query, args...).Scan(&ids)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return ErrRecordNotFound
+	}
+
+	before, err := snapshotTasks(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE tasks SET deleted_at = NULL WHERE id = ANY($1)`, ids)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "tasks_title_project_id_key"):
+			return ErrDuplicateTask
+		default:
+			return err
+		}
+	}
+
+	err = recordTaskEvents(ctx, tx, ids, userID, TaskUpdated, before)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EmptyTrash permanently removes the user's trashed tasks with their attachments
+// and returns how many tasks were removed
+func (m *TasksModel) EmptyTrash(ctx context.Context, userID string) (int64, error) {
+	query := `DELETE FROM tasks
+	WHERE user_id = $1 AND deleted_at IS NOT NULL`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	attachments, err := deleteAttachments(ctx, tx, `SELECT id FROM tasks WHERE user_id = $1 AND deleted_at IS NOT NULL`, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), deleteBlobs(ctx, m.Blobs, attachments)
+}
+
+// Purge permanently removes every task trashed before the given time with its
+// attachments and returns how many tasks were removed
+func (m *TasksModel) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM tasks
+	WHERE deleted_at < $1`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	attachments, err := deleteAttachments(ctx, tx, `SELECT id FROM tasks WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), deleteBlobs(ctx, m.Blobs, attachments)
+}
@@ -0,0 +1,99 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTasksTrash(t *testing.T) {
+	t.Run("delete and restore", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		parent := testSubtask(t, tasks, u.ID, nil)
+		child := testSubtask(t, tasks, u.ID, &parent.ID)
+
+		require.NoError(t, tasks.Delete(context.Background(), parent.ID, u.ID, 0))
+
+		_, err := tasks.GetByID(context.Background(), child.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+
+		listed, _, err := tasks.All(context.Background(), u.ID, models.TaskFilter{})
+		require.NoError(t, err)
+		require.Empty(t, listed)
+
+		trashed, err := tasks.Trash(context.Background(), u.ID)
+		require.NoError(t, err)
+		require.Len(t, trashed, 1)
+		require.Equal(t, parent.ID, trashed[0].ID)
+		require.NotNil(t, trashed[0].DeletedAt)
+
+		err = tasks.Restore(context.Background(), child.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+
+		require.NoError(t, tasks.Restore(context.Background(), parent.ID, u.ID))
+
+		restored, err := tasks.GetByID(context.Background(), parent.ID, u.ID)
+		require.NoError(t, err)
+		require.Nil(t, restored.DeletedAt)
+		require.Equal(t, 1, restored.Progress.Total)
+	})
+
+	t.Run("restore over open duplicate", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		first := &models.Task{ID: db.NewID(), UserID: u.ID, Title: "pay rent", Description: gofakeit.Phrase()}
+		require.NoError(t, tasks.Create(context.Background(), first))
+		require.NoError(t, tasks.Delete(context.Background(), first.ID, u.ID, 0))
+
+		second := &models.Task{ID: db.NewID(), UserID: u.ID, Title: "pay rent", Description: gofakeit.Phrase()}
+		require.NoError(t, tasks.Create(context.Background(), second))
+
+		err := tasks.Restore(context.Background(), first.ID, u.ID)
+		require.ErrorIs(t, err, models.ErrDuplicateTask)
+	})
+
+	// purging reaches every user so this subtest does not run alongside the others
+	t.Run("empty and purge", func(t *testing.T) {
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool, Blobs: testBlobs(t)}
+		old := testSubtask(t, tasks, u.ID, nil)
+		recent := testSubtask(t, tasks, u.ID, nil)
+
+		require.NoError(t, tasks.Delete(context.Background(), old.ID, u.ID, 0))
+
+		n, err := tasks.Purge(context.Background(), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, n, int64(1))
+
+		require.NoError(t, tasks.Delete(context.Background(), recent.ID, u.ID, 0))
+
+		n, err = tasks.Purge(context.Background(), time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		n, err = tasks.EmptyTrash(context.Background(), u.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		trashed, err := tasks.Trash(context.Background(), u.ID)
+		require.NoError(t, err)
+		require.Empty(t, trashed)
+	})
+}
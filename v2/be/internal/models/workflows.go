@@ -0,0 +1,355 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"v2/be/internal/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrStatusNotFound    = errors.New("status not found")
+	ErrInvalidTransition = errors.New("status transition not allowed")
+	ErrStatusInUse       = errors.New("status still has tasks")
+)
+
+// doneStatus and initialStatus select the terminal and the first open status of
+// the workflow owning the task row being written
+const (
+	doneStatus    = `(SELECT s.name FROM statuses s WHERE s.user_id = tasks.user_id AND s.done)`
+	initialStatus = `(SELECT s.name FROM statuses s WHERE s.user_id = tasks.user_id AND NOT s.done ORDER BY s.position LIMIT 1)`
+)
+
+// Status is a column of the user's board. Exactly one status of a workflow is
+// done, and tasks in it are completed.
+type Status struct {
+	Name string `json:"name"`
+	Done bool   `json:"done"`
+}
+
+// Transition allows a task to move from one status to another
+type Transition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Workflow lists the statuses of a user in board order and the moves allowed between them
+type Workflow struct {
+	Statuses    []Status     `json:"statuses"`
+	Transitions []Transition `json:"transitions"`
+}
+
+// DefaultWorkflow is the workflow every user starts with
+var DefaultWorkflow = Workflow{
+	Statuses: []Status{
+		{Name: "todo"},
+		{Name: "in_progress"},
+		{Name: "review"},
+		{Name: "done", Done: true},
+	},
+	Transitions: []Transition{
+		{From: "todo", To: "in_progress"},
+		{From: "in_progress", To: "todo"},
+		{From: "in_progress", To: "review"},
+		{From: "review", To: "in_progress"},
+		{From: "review", To: "done"},
+		{From: "done", To: "review"},
+	},
+}
+
+type WorkflowsModel struct {
+	Pool *pgxpool.Pool
+}
+
+// Get returns the user's workflow, creating the default one on first use
+func (m *WorkflowsModel) Get(ctx context.Context, userID string) (*Workflow, error) {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = ensureWorkflow(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Workflow{}
+
+	rows, err := tx.Query(ctx, `SELECT name, done FROM statuses WHERE user_id = $1 ORDER BY position`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var s Status
+
+		serr := rows.Scan(&s.Name, &s.Done)
+		if serr != nil {
+			return nil, serr
+		}
+
+		w.Statuses = append(w.Statuses, s)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.Query(ctx, `SELECT t.from_status, t.to_status
+	FROM status_transitions t
+		JOIN statuses f ON f.user_id = t.user_id AND f.name = t.from_status
+		JOIN statuses s ON s.user_id = t.user_id AND s.name = t.to_status
+	WHERE t.user_id = $1
+	ORDER BY f.position, s.position`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var t Transition
+
+		terr := rows.Scan(&t.From, &t.To)
+		if terr != nil {
+			return nil, terr
+		}
+
+		w.Transitions = append(w.Transitions, t)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Replace swaps the user's workflow for w. Statuses are matched by name, and
+// removing a status that still has tasks fails with ErrStatusInUse. So does
+// moving the done flag to or from a status that has tasks, as its tasks would
+// be completed or reopened without the checks of Complete and Reopen.
+func (m *WorkflowsModel) Replace(ctx context.Context, userID string, w *Workflow) error {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var done []string
+	for _, s := range w.Statuses {
+		if s.Done {
+			done = append(done, s.Name)
+		}
+	}
+
+	var busy bool
+
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tasks t
+		JOIN statuses s ON s.user_id = t.user_id AND s.name = t.status
+	WHERE t.user_id = $1 AND s.done <> (s.name = ANY($2)))`, userID, done).Scan(&busy)
+	if err != nil {
+		return err
+	}
+
+	if busy {
+		return ErrStatusInUse
+	}
+
+	err = saveWorkflow(ctx, tx, userID, w)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetStatus moves a task to another status of the user's workflow along an
+// allowed transition. Moving into the done status completes the task with the
+// same checks as Complete, and moving out of it reopens the task.
+func (m *TasksModel) SetStatus(ctx context.Context, id, userID, status string) error {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var done bool
+
+	err = tx.QueryRow(ctx, `SELECT done FROM statuses WHERE user_id = $1 AND name = $2`, userID, status).Scan(&done)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrStatusNotFound
+		default:
+			return err
+		}
+	}
+
+	var current string
+	var completed bool
+
+	err = tx.QueryRow(ctx, `SELECT status, completed FROM tasks
+	WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`, id, userID).Scan(&current, &completed)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	var allowed bool
+
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM status_transitions
+	WHERE user_id = $1 AND from_status = $2 AND to_status = $3)`, userID, current, status).Scan(&allowed)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return ErrInvalidTransition
+	}
+
+	switch {
+	case done:
+		err = completeTask(ctx, tx, id, userID, false)
+		if err != nil {
+			return err
+		}
+	case completed:
+		err = reopenTask(ctx, tx, id, userID, status)
+		if err != nil {
+			return err
+		}
+	default:
+		before, serr := snapshotTask(ctx, tx, id)
+		if serr != nil {
+			return serr
+		}
+
+		result, uerr := tx.Exec(ctx, `UPDATE tasks SET status = $1 WHERE id = $2 AND user_id = $3`, status, id, userID)
+		if uerr != nil {
+			return uerr
+		}
+
+		if result.RowsAffected() != 1 {
+			return ErrOpFailed
+		}
+
+		err = recordTaskEvent(ctx, tx, id, userID, TaskUpdated, before)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ensureWorkflow gives the user the default workflow when they have none yet.
+func ensureWorkflow(ctx context.Context, tx pgx.Tx, userID string) error {
+	var exists bool
+
+	err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM statuses WHERE user_id = $1)`, userID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	return saveWorkflow(ctx, tx, userID, &DefaultWorkflow)
+}
+
+// saveWorkflow writes w as the user's workflow, keeping statuses that are
+// still listed.
+func saveWorkflow(ctx context.Context, tx pgx.Tx, userID string, w *Workflow) error {
+	// only one status may be done at a time, so clear the flag before moving it
+	_, err := tx.Exec(ctx, `UPDATE statuses SET done = false WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(w.Statuses))
+
+	for i, s := range w.Statuses {
+		_, err = tx.Exec(ctx, `INSERT INTO statuses (id, user_id, name, position, done)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name, user_id) DO UPDATE SET position = EXCLUDED.position, done = EXCLUDED.done`,
+			db.NewID(), userID, s.Name, i, s.Done)
+		if err != nil {
+			return err
+		}
+
+		names = append(names, s.Name)
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM status_transitions WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range w.Transitions {
+		_, err = tx.Exec(ctx, `INSERT INTO status_transitions (user_id, from_status, to_status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`, userID, t.From, t.To)
+		if err != nil {
+			switch {
+			case strings.Contains(db.FormatErr(err), "status_transitions_from_status_fkey"),
+				strings.Contains(db.FormatErr(err), "status_transitions_to_status_fkey"):
+				return ErrStatusNotFound
+			default:
+				return err
+			}
+		}
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM statuses WHERE user_id = $1 AND NOT (name = ANY($2))`, userID, names)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "tasks_status_fkey"):
+			return ErrStatusInUse
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
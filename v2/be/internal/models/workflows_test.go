@@ -0,0 +1,159 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflows(t *testing.T) {
+	t.Run("default workflow", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		workflows := &models.WorkflowsModel{Pool: pool}
+
+		w, err := workflows.Get(context.Background(), u.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.DefaultWorkflow.Statuses, w.Statuses)
+		require.ElementsMatch(t, models.DefaultWorkflow.Transitions, w.Transitions)
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		ct, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "todo", ct.Status)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		workflows := &models.WorkflowsModel{Pool: pool}
+
+		busy := &models.Workflow{
+			Statuses: []models.Status{{Name: "backlog"}, {Name: "shipped", Done: true}},
+		}
+
+		err := workflows.Replace(context.Background(), u.ID, busy)
+		require.ErrorIs(t, err, models.ErrStatusInUse)
+
+		w := &models.Workflow{
+			Statuses:    []models.Status{{Name: "todo"}, {Name: "shipped", Done: true}},
+			Transitions: []models.Transition{{From: "todo", To: "shipped"}},
+		}
+
+		require.NoError(t, workflows.Replace(context.Background(), u.ID, w))
+
+		got, err := workflows.Get(context.Background(), u.ID)
+		require.NoError(t, err)
+		require.Equal(t, w.Statuses, got.Statuses)
+		require.Equal(t, w.Transitions, got.Transitions)
+
+		require.NoError(t, tasks.SetStatus(context.Background(), task.ID, u.ID, "shipped"))
+
+		st, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.True(t, st.Completed)
+	})
+
+	t.Run("moving done over tasks", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+		require.NoError(t, tasks.Complete(context.Background(), task.ID, u.ID, 0, false))
+
+		workflows := &models.WorkflowsModel{Pool: pool}
+
+		reopen := &models.Workflow{
+			Statuses: []models.Status{{Name: "todo", Done: true}, {Name: "done"}},
+		}
+
+		err := workflows.Replace(context.Background(), u.ID, reopen)
+		require.ErrorIs(t, err, models.ErrStatusInUse)
+
+		st, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.True(t, st.Completed)
+		require.Equal(t, "done", st.Status)
+
+		renamed := &models.Workflow{
+			Statuses: []models.Status{{Name: "todo"}, {Name: "doing"}, {Name: "done", Done: true}},
+		}
+
+		require.NoError(t, workflows.Replace(context.Background(), u.ID, renamed))
+	})
+}
+
+func TestTasksSetStatus(t *testing.T) {
+	t.Run("follows transitions", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		err := tasks.SetStatus(context.Background(), task.ID, u.ID, "review")
+		require.ErrorIs(t, err, models.ErrInvalidTransition)
+
+		err = tasks.SetStatus(context.Background(), task.ID, u.ID, "blocked")
+		require.ErrorIs(t, err, models.ErrStatusNotFound)
+
+		for _, s := range []string{"in_progress", "review", "done"} {
+			require.NoError(t, tasks.SetStatus(context.Background(), task.ID, u.ID, s))
+		}
+
+		dt, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "done", dt.Status)
+		require.True(t, dt.Completed)
+		require.NotNil(t, dt.CompletedAt)
+
+		require.NoError(t, tasks.SetStatus(context.Background(), task.ID, u.ID, "review"))
+
+		rt, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "review", rt.Status)
+		require.False(t, rt.Completed)
+		require.Nil(t, rt.CompletedAt)
+	})
+
+	t.Run("complete shortcut", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		u := testUser(t, &models.UsersModel{Pool: pool})
+
+		tasks := &models.TasksModel{Pool: pool}
+		task := testSubtask(t, tasks, u.ID, nil)
+
+		require.NoError(t, tasks.Complete(context.Background(), task.ID, u.ID, 0, false))
+
+		ct, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "done", ct.Status)
+
+		require.NoError(t, tasks.Reopen(context.Background(), task.ID, u.ID))
+
+		ot, err := tasks.GetByID(context.Background(), task.ID, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "todo", ot.Status)
+	})
+}
@@ -0,0 +1,373 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"v2/be/internal/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrMemberUserNotFound = errors.New("user not found")
+	ErrDuplicateMember    = errors.New("user is already a member of this workspace")
+	ErrRemoveOwner        = errors.New("the owner cannot be removed from the workspace")
+)
+
+// WorkspaceRole is the part a user plays in a workspace. Owners and admins
+// manage the workspace and every task in it, members work on its tasks and
+// guests can only read them.
+type WorkspaceRole string
+
+const (
+	WorkspaceGuest  WorkspaceRole = "guest"
+	WorkspaceMember WorkspaceRole = "member"
+	WorkspaceAdmin  WorkspaceRole = "admin"
+	WorkspaceOwner  WorkspaceRole = "owner"
+)
+
+// InviteRoles lists the roles a user can be invited into a workspace with
+var InviteRoles = []string{string(WorkspaceAdmin), string(WorkspaceMember), string(WorkspaceGuest)}
+
+var workspaceRoleRanks = map[WorkspaceRole]int{
+	WorkspaceGuest:  1,
+	WorkspaceMember: 2,
+	WorkspaceAdmin:  3,
+	WorkspaceOwner:  4,
+}
+
+// Allows reports whether r grants at least the rights of min
+func (r WorkspaceRole) Allows(min WorkspaceRole) bool {
+	return workspaceRoleRanks[r] >= workspaceRoleRanks[min]
+}
+
+// taskRole returns the access r grants to the tasks of the workspace
+func (r WorkspaceRole) taskRole() Role {
+	switch r {
+	case WorkspaceOwner, WorkspaceAdmin:
+		return RoleOwner
+	case WorkspaceMember:
+		return RoleEditor
+	case WorkspaceGuest:
+		return RoleViewer
+	default:
+		return ""
+	}
+}
+
+// Workspace is a space shared by a team, Role being the part the user reading it plays
+type Workspace struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Role      WorkspaceRole `json:"role"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// Member is a user belonging to a workspace
+type Member struct {
+	WorkspaceID string        `json:"workspace_id"`
+	UserID      string        `json:"user_id"`
+	Username    string        `json:"username"`
+	Role        WorkspaceRole `json:"role"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type WorkspacesModel struct {
+	Pool *pgxpool.Pool
+}
+
+// Create adds a workspace owned by userID
+func (m *WorkspacesModel) Create(ctx context.Context, w *Workspace, userID string) error {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `INSERT INTO workspaces (id, name) VALUES ($1, $2)
+	RETURNING created_at`, w.ID, w.Name).Scan(&w.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO workspace_members (workspace_id, user_id, role)
+	VALUES ($1, $2, 'owner')`, w.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	w.Role = WorkspaceOwner
+
+	return nil
+}
+
+// All returns the workspaces the user belongs to, oldest first
+func (m *WorkspacesModel) All(ctx context.Context, userID string) ([]*Workspace, error) {
+	query := `SELECT workspaces.id, workspaces.name, workspace_members.role::TEXT, workspaces.created_at
+	FROM workspaces
+		JOIN workspace_members ON workspace_members.workspace_id = workspaces.id
+	WHERE workspace_members.user_id = $1
+	ORDER BY workspaces.id`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var workspaces []*Workspace
+
+	rows, err := tx.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var w Workspace
+
+		serr := rows.Scan(&w.ID, &w.Name, &w.Role, &w.CreatedAt)
+		if serr != nil {
+			return nil, serr
+		}
+
+		workspaces = append(workspaces, &w)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+// Role returns the part the user plays in a workspace, failing with
+// ErrRecordNotFound when the workspace is missing or the user is not a member
+func (m *WorkspacesModel) Role(ctx context.Context, id, userID string) (WorkspaceRole, error) {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var role WorkspaceRole
+
+	err = tx.QueryRow(ctx, `SELECT role::TEXT FROM workspace_members
+	WHERE workspace_id = $1 AND user_id = $2`, id, userID).Scan(&role)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return "", ErrRecordNotFound
+		default:
+			return "", err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return role, nil
+}
+
+// Delete removes a workspace. Its tasks stay with the users who created them.
+func (m *WorkspacesModel) Delete(ctx context.Context, id string) error {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `DELETE FROM workspaces WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrRecordNotFound
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Members returns the members of a workspace, oldest first
+func (m *WorkspacesModel) Members(ctx context.Context, id string) ([]*Member, error) {
+	query := `SELECT workspace_members.workspace_id, workspace_members.user_id, users.username,
+		workspace_members.role::TEXT, workspace_members.created_at
+	FROM workspace_members
+		JOIN users ON users.id = workspace_members.user_id
+	WHERE workspace_members.workspace_id = $1
+	ORDER BY workspace_members.created_at, users.username`
+
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadOnly,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var members []*Member
+
+	rows, err := tx.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var mb Member
+
+		serr := rows.Scan(&mb.WorkspaceID, &mb.UserID, &mb.Username, &mb.Role, &mb.CreatedAt)
+		if serr != nil {
+			return nil, serr
+		}
+
+		members = append(members, &mb)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// Invite adds mb.Username to a workspace with mb.Role
+func (m *WorkspacesModel) Invite(ctx context.Context, mb *Member) error {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `SELECT id FROM users WHERE username = $1`, mb.Username).Scan(&mb.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrMemberUserNotFound
+		default:
+			return err
+		}
+	}
+
+	err = tx.QueryRow(ctx, `INSERT INTO workspace_members (workspace_id, user_id, role)
+	VALUES ($1, $2, $3)
+	RETURNING created_at`, mb.WorkspaceID, mb.UserID, mb.Role).Scan(&mb.CreatedAt)
+	if err != nil {
+		switch {
+		case strings.Contains(db.FormatErr(err), "workspace_members_pkey"):
+			return ErrDuplicateMember
+		default:
+			return err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveMember takes a user out of a workspace. The owner cannot be removed.
+func (m *WorkspacesModel) RemoveMember(ctx context.Context, id, userID string) error {
+	tx, err := m.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var role WorkspaceRole
+
+	err = tx.QueryRow(ctx, `SELECT role::TEXT FROM workspace_members
+	WHERE workspace_id = $1 AND user_id = $2`, id, userID).Scan(&role)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	if role == WorkspaceOwner {
+		return ErrRemoveOwner
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM workspace_members
+	WHERE workspace_id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() != 1 {
+		return ErrOpFailed
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"v2/be/internal/db"
+	"v2/be/internal/models"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaces(t *testing.T) {
+	t.Run("membership", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		owner := testUser(t, users)
+		other := testUser(t, users)
+
+		workspaces := &models.WorkspacesModel{Pool: pool}
+
+		w := &models.Workspace{ID: db.NewID(), Name: "platform"}
+		require.NoError(t, workspaces.Create(context.Background(), w, owner.ID))
+		require.Equal(t, models.WorkspaceOwner, w.Role)
+
+		_, err := workspaces.Role(context.Background(), w.ID, other.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+
+		mb := &models.Member{WorkspaceID: w.ID, Username: other.Username, Role: models.WorkspaceGuest}
+		require.NoError(t, workspaces.Invite(context.Background(), mb))
+		require.Equal(t, other.ID, mb.UserID)
+
+		dup := &models.Member{WorkspaceID: w.ID, Username: other.Username, Role: models.WorkspaceAdmin}
+		require.ErrorIs(t, workspaces.Invite(context.Background(), dup), models.ErrDuplicateMember)
+
+		unknown := &models.Member{WorkspaceID: w.ID, Username: "nobody-" + db.NewID(), Role: models.WorkspaceMember}
+		require.ErrorIs(t, workspaces.Invite(context.Background(), unknown), models.ErrMemberUserNotFound)
+
+		role, err := workspaces.Role(context.Background(), w.ID, other.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.WorkspaceGuest, role)
+
+		all, err := workspaces.All(context.Background(), other.ID)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, models.WorkspaceGuest, all[0].Role)
+
+		members, err := workspaces.Members(context.Background(), w.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+
+		require.ErrorIs(t, workspaces.RemoveMember(context.Background(), w.ID, owner.ID), models.ErrRemoveOwner)
+		require.NoError(t, workspaces.RemoveMember(context.Background(), w.ID, other.ID))
+		require.ErrorIs(t, workspaces.RemoveMember(context.Background(), w.ID, other.ID), models.ErrRecordNotFound)
+
+		require.NoError(t, workspaces.Delete(context.Background(), w.ID))
+		require.ErrorIs(t, workspaces.Delete(context.Background(), w.ID), models.ErrRecordNotFound)
+	})
+
+	t.Run("tasks", func(t *testing.T) {
+		t.Parallel()
+
+		pool := testPool(t)
+		users := &models.UsersModel{Pool: pool}
+		owner := testUser(t, users)
+		member := testUser(t, users)
+		guest := testUser(t, users)
+
+		workspaces := &models.WorkspacesModel{Pool: pool}
+
+		w := &models.Workspace{ID: db.NewID(), Name: "platform"}
+		require.NoError(t, workspaces.Create(context.Background(), w, owner.ID))
+		require.NoError(t, workspaces.Invite(context.Background(), &models.Member{WorkspaceID: w.ID, Username: member.Username, Role: models.WorkspaceMember}))
+		require.NoError(t, workspaces.Invite(context.Background(), &models.Member{WorkspaceID: w.ID, Username: guest.Username, Role: models.WorkspaceGuest}))
+
+		tasks := &models.TasksModel{Pool: pool}
+
+		task := &models.Task{ID: db.NewID(), UserID: member.ID, WorkspaceID: &w.ID, Title: "launch", Description: gofakeit.Phrase()}
+		require.NoError(t, tasks.Create(context.Background(), task))
+
+		sub := testSubtask(t, tasks, member.ID, &task.ID)
+
+		rs, err := tasks.GetByID(context.Background(), sub.ID, member.ID)
+		require.NoError(t, err)
+		require.Equal(t, &w.ID, rs.WorkspaceID)
+
+		testSubtask(t, tasks, owner.ID, nil)
+
+		listed, _, err := tasks.All(context.Background(), guest.ID, models.TaskFilter{WorkspaceID: w.ID})
+		require.NoError(t, err)
+		require.Len(t, listed, 2)
+
+		access := &models.AccessModel{Pool: pool}
+
+		a, err := access.Task(context.Background(), task.ID, owner.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.RoleOwner, a.Role)
+		require.Equal(t, member.ID, a.OwnerID)
+		require.Equal(t, &w.ID, a.WorkspaceID)
+
+		a, err = access.Task(context.Background(), task.ID, guest.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.RoleViewer, a.Role)
+
+		comments := &models.CommentsModel{Pool: pool}
+		c := &models.Comment{ID: db.NewID(), TaskID: task.ID, UserID: owner.ID, Body: "on it"}
+		require.NoError(t, comments.Create(context.Background(), c))
+
+		require.NoError(t, workspaces.Delete(context.Background(), w.ID))
+
+		_, err = access.Task(context.Background(), task.ID, guest.ID)
+		require.ErrorIs(t, err, models.ErrRecordNotFound)
+
+		rt, err := tasks.GetByID(context.Background(), task.ID, member.ID)
+		require.NoError(t, err)
+		require.Nil(t, rt.WorkspaceID)
+	})
+}
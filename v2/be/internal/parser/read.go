@@ -1,11 +1,14 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"path/filepath"
 	"strings"
 
 	"github.com/microcosm-cc/bluemonday"
@@ -56,6 +59,131 @@ func Read(w http.ResponseWriter, r *http.Request, dst any) error {
 	return nil
 }
 
+// MergePatch is the media type of a JSON Merge Patch (RFC 7396) body
+const MergePatch = "application/merge-patch+json"
+
+// HasContentType reports whether the body of r is of the given media type,
+// ignoring any parameters such as the charset
+func HasContentType(r *http.Request, mediaType string) bool {
+	t, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	return t == mediaType
+}
+
+// Field is a JSON value that remembers whether its key was in the body, so
+// an absent key can be told apart from one set to null or to an empty value
+type Field[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON is only called for keys present in the body, null included
+func (f *Field[T]) UnmarshalJSON(b []byte) error {
+	f.Set = true
+
+	if string(b) == "null" {
+		f.Null = true
+		return nil
+	}
+
+	err := json.Unmarshal(b, &f.Value)
+
+	// the decoder does not name the key of errors returned here, and the
+	// offset is relative to the value, so only the types are reported
+	var unmarshalTypeError *json.UnmarshalTypeError
+	if errors.As(err, &unmarshalTypeError) {
+		return fmt.Errorf("body contains a JSON %s where %s is expected", unmarshalTypeError.Value, unmarshalTypeError.Type)
+	}
+
+	return err
+}
+
 func Sanitize(s string) string {
 	return bluemonday.NewPolicy().Sanitize(strings.TrimSpace(s))
 }
+
+var ErrFileTooLarge = errors.New("file is too large")
+
+// File is a file uploaded in a multipart form. ContentType is sniffed from
+// the content rather than trusted from the client.
+type File struct {
+	Name        string
+	ContentType string
+	Body        io.Reader
+}
+
+// ReadFile returns the file sent under field in a multipart/form-data body.
+// The body is streamed, and reading past maxBytes of file content fails with ErrFileTooLarge.
+func ReadFile(w http.ResponseWriter, r *http.Request, field string, maxBytes int64) (*File, error) {
+	// leave room for the part headers and any other form fields
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+1_048_576)
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, errors.New("body must be multipart/form-data")
+	}
+
+	for {
+		part, perr := mr.NextPart()
+		if perr != nil {
+			var maxBytesError *http.MaxBytesError
+			switch {
+			case errors.Is(perr, io.EOF):
+				return nil, fmt.Errorf("body must contain a %q file", field)
+			case errors.As(perr, &maxBytesError):
+				return nil, fmt.Errorf("file must not be larger than %d bytes: %w", maxBytes, ErrFileTooLarge)
+			default:
+				return nil, errors.New("body contains a badly-formed multipart form")
+			}
+		}
+
+		if part.FormName() != field || part.FileName() == "" {
+			continue
+		}
+
+		body := &fileReader{r: part, left: maxBytes, limit: maxBytes}
+
+		head := make([]byte, 512)
+
+		n, rerr := io.ReadFull(body, head)
+		if rerr != nil && !errors.Is(rerr, io.ErrUnexpectedEOF) && !errors.Is(rerr, io.EOF) {
+			return nil, rerr
+		}
+
+		if n == 0 {
+			return nil, errors.New("file must not be empty")
+		}
+
+		f := &File{
+			Name:        filepath.Base(part.FileName()),
+			ContentType: http.DetectContentType(head[:n]),
+			Body:        io.MultiReader(bytes.NewReader(head[:n]), body),
+		}
+
+		return f, nil
+	}
+}
+
+// fileReader reads a file part, failing once more than limit bytes were read
+type fileReader struct {
+	r     io.Reader
+	left  int64
+	limit int64
+}
+
+func (f *fileReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	f.left -= int64(n)
+
+	var maxBytesError *http.MaxBytesError
+	switch {
+	case f.left < 0, errors.As(err, &maxBytesError):
+		return n, fmt.Errorf("file must not be larger than %d bytes: %w", f.limit, ErrFileTooLarge)
+	default:
+		return n, err
+	}
+}
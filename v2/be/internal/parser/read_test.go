@@ -1,6 +1,9 @@
 package parser_test
 
 import (
+	"bytes"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -89,3 +92,158 @@ func TestRead(t *testing.T) {
 		}
 	})
 }
+
+func TestReadField(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		set   bool
+		null  bool
+		value string
+	}{
+		{name: "absent", body: `{}`},
+		{name: "null", body: `{"name": null}`, set: true, null: true},
+		{name: "empty", body: `{"name": ""}`, set: true},
+		{name: "value", body: `{"name": "input"}`, set: true, value: "input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tt.body))
+
+			var input struct {
+				Name parser.Field[string] `json:"name"`
+			}
+
+			err := parser.Read(w, r, &input)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.set, input.Name.Set)
+			require.Equal(t, tt.null, input.Name.Null)
+			require.Equal(t, tt.value, input.Name.Value)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			body string
+			want string
+		}{
+			{body: `{"name": 234}`, want: "body contains a JSON number where string is expected"},
+			{body: `{"age": "234"}`, want: `body contains unknown key "age"`},
+		}
+
+		for _, tt := range tests {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tt.body))
+
+			var input struct {
+				Name parser.Field[string] `json:"name"`
+			}
+
+			err := parser.Read(w, r, &input)
+			require.EqualError(t, err, tt.want)
+		}
+	})
+}
+
+func TestHasContentType(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{header: "application/merge-patch+json", want: true},
+		{header: "application/merge-patch+json; charset=utf-8", want: true},
+		{header: "application/json", want: false},
+		{header: "", want: false},
+		{header: "not a media type;;", want: false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPatch, "/", nil)
+		r.Header.Set("Content-Type", tt.header)
+
+		require.Equal(t, tt.want, parser.HasContentType(r, parser.MergePatch), tt.header)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	form := func(t *testing.T, field string, content []byte) (*bytes.Buffer, string) {
+		t.Helper()
+
+		var b bytes.Buffer
+
+		mw := multipart.NewWriter(&b)
+
+		fw, err := mw.CreateFormFile(field, "dir/notes.txt")
+		require.NoError(t, err)
+
+		_, err = fw.Write(content)
+		require.NoError(t, err)
+
+		require.NoError(t, mw.Close())
+
+		return &b, mw.FormDataContentType()
+	}
+
+	t.Run("normal", func(t *testing.T) {
+		body, contentType := form(t, "file", []byte("plain notes"))
+
+		r := httptest.NewRequest(http.MethodPost, "/", body)
+		r.Header.Set("Content-Type", contentType)
+
+		f, err := parser.ReadFile(httptest.NewRecorder(), r, "file", 1024)
+		require.NoError(t, err)
+
+		require.Equal(t, "notes.txt", f.Name)
+		require.Equal(t, "text/plain; charset=utf-8", f.ContentType)
+
+		b, err := io.ReadAll(f.Body)
+		require.NoError(t, err)
+		require.Equal(t, "plain notes", string(b))
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		body, contentType := form(t, "file", bytes.Repeat([]byte("a"), 2048))
+
+		r := httptest.NewRequest(http.MethodPost, "/", body)
+		r.Header.Set("Content-Type", contentType)
+
+		f, err := parser.ReadFile(httptest.NewRecorder(), r, "file", 1024)
+		require.NoError(t, err)
+
+		_, err = io.ReadAll(f.Body)
+		require.ErrorIs(t, err, parser.ErrFileTooLarge)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			field   string
+			content []byte
+		}{
+			{name: "missing field", field: "upload", content: []byte("notes")},
+			{name: "empty file", field: "file", content: nil},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				body, contentType := form(t, tt.field, tt.content)
+
+				r := httptest.NewRequest(http.MethodPost, "/", body)
+				r.Header.Set("Content-Type", contentType)
+
+				_, err := parser.ReadFile(httptest.NewRecorder(), r, "file", 1024)
+				require.Error(t, err)
+			})
+		}
+
+		t.Run("not multipart", func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"file": "notes"}`))
+
+			_, err := parser.ReadFile(httptest.NewRecorder(), r, "file", 1024)
+			require.Error(t, err)
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"slices"
 	"strings"
 
 	pv "github.com/wagslane/go-password-validator"
@@ -45,6 +46,13 @@ func (v *Validator) AddError(field, message string) {
 	}
 }
 
+// Check adds an error for field when ok is false
+func (v *Validator) Check(ok bool, field, message string) {
+	if !ok {
+		v.AddError(field, message)
+	}
+}
+
 // RequiredString ensures that a string is not empty
 func (v *Validator) RequiredString(s, field, message string) {
 	empty := len(strings.TrimSpace(s)) == 0
@@ -68,3 +76,8 @@ func (v *Validator) CheckPassword(password string, field string) {
 		v.AddError(field, err.Error())
 	}
 }
+
+// PermittedValue reports whether value is one of the permitted values
+func PermittedValue[T comparable](value T, permitted ...T) bool {
+	return slices.Contains(permitted, value)
+}
@@ -8,6 +8,27 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestCheck(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		v := validator.New()
+		v.Check(true, "input", validator.Required)
+
+		require.True(t, v.Valid())
+	})
+
+	t.Run("not ok", func(t *testing.T) {
+		t.Parallel()
+
+		v := validator.New()
+		v.Check(false, "input", validator.Required)
+
+		require.False(t, v.Valid())
+		require.Contains(t, v.Errors(), "input")
+	})
+}
+
 func TestRequiredString(t *testing.T) {
 	t.Run("normal", func(t *testing.T) {
 		t.Parallel()
@@ -148,3 +169,30 @@ func TestCheckPassword(t *testing.T) {
 		require.Contains(t, v.Errors(), "password")
 	})
 }
+
+func TestPermittedValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect bool
+	}{
+		{
+			name:   "permitted",
+			input:  "title",
+			expect: true,
+		},
+		{
+			name:   "not permitted",
+			input:  "size",
+			expect: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expect, validator.PermittedValue(tt.input, "title", "-title"))
+		})
+	}
+}
@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+    DROP CONSTRAINT IF EXISTS tasks_remind_at_check,
+    DROP COLUMN IF EXISTS remind_at,
+    DROP COLUMN IF EXISTS due_at;
@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS remind_at TIMESTAMPTZ,
+    ADD CONSTRAINT tasks_remind_at_check CHECK (remind_at IS NULL OR due_at IS NULL OR remind_at <= due_at);
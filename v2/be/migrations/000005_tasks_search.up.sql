@@ -0,0 +1,7 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS search TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', title), 'A') ||
+        setweight(to_tsvector('english', description), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS tasks_search_idx ON tasks USING GIN (search);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS task_tags_tag_id_idx;
+
+DROP TABLE IF EXISTS task_tags;
+
+DROP TABLE IF EXISTS tags;
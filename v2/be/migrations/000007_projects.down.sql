@@ -0,0 +1,8 @@
+ALTER TABLE tasks
+    DROP CONSTRAINT IF EXISTS tasks_title_project_id_key,
+    ADD CONSTRAINT tasks_title_user_id_key UNIQUE (title, user_id),
+    DROP COLUMN IF EXISTS project_id;
+
+DROP INDEX IF EXISTS projects_inbox_idx;
+
+DROP TABLE IF EXISTS projects;
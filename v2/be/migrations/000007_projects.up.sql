@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS projects (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (name <> ''),
+    inbox BOOLEAN NOT NULL DEFAULT false,
+    UNIQUE(name, user_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS projects_inbox_idx ON projects (user_id) WHERE inbox;
+
+INSERT INTO projects (id, user_id, name, inbox)
+SELECT gen_random_uuid()::TEXT, id, 'Inbox', true FROM users
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_id TEXT REFERENCES projects (id) ON DELETE CASCADE;
+
+UPDATE tasks SET project_id = projects.id
+FROM projects
+WHERE projects.user_id = tasks.user_id AND projects.inbox;
+
+ALTER TABLE tasks
+    ALTER COLUMN project_id SET NOT NULL,
+    DROP CONSTRAINT IF EXISTS tasks_title_user_id_key,
+    ADD CONSTRAINT tasks_title_project_id_key UNIQUE (title, project_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tasks_title_project_id_key;
+
+ALTER TABLE tasks ADD CONSTRAINT tasks_title_project_id_key UNIQUE (title, project_id);
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence;
@@ -0,0 +1,6 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence TEXT CHECK (recurrence <> '');
+
+-- each completed occurrence keeps its title, so only open tasks need unique titles
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_title_project_id_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_title_project_id_key ON tasks (title, project_id) WHERE NOT completed;
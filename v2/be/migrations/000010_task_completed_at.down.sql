@@ -0,0 +1,3 @@
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_completed_at_check;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS completed_at;
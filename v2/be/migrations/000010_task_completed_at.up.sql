@@ -0,0 +1,3 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+
+ALTER TABLE tasks ADD CONSTRAINT tasks_completed_at_check CHECK (completed_at IS NULL OR completed);
@@ -0,0 +1,9 @@
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS tasks_title_project_id_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_title_project_id_key ON tasks (title, project_id) WHERE NOT completed;
+
+DROP INDEX IF EXISTS tasks_deleted_at_idx;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,8 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS tasks_deleted_at_idx ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- trashed tasks no longer hold on to their title
+DROP INDEX IF EXISTS tasks_title_project_id_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_title_project_id_key ON tasks (title, project_id) WHERE NOT completed AND deleted_at IS NULL;
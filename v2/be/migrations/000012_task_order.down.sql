@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS tasks_user_id_position_idx;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS priority;
+
+DROP TYPE IF EXISTS task_priority;
@@ -0,0 +1,17 @@
+CREATE TYPE task_priority AS ENUM ('none', 'low', 'medium', 'high', 'urgent');
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS priority task_priority NOT NULL DEFAULT 'none',
+    ADD COLUMN IF NOT EXISTS position TEXT COLLATE "C";
+
+-- existing tasks keep their creation order, with keys that never end in the zero digit
+UPDATE tasks SET position = ordered.position
+FROM (
+    SELECT id, lpad(row_number() OVER (PARTITION BY user_id ORDER BY id)::TEXT, 12, '0') || 'V' AS position
+    FROM tasks
+) ordered
+WHERE ordered.id = tasks.id;
+
+ALTER TABLE tasks ALTER COLUMN position SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS tasks_user_id_position_idx ON tasks (user_id, position, id);
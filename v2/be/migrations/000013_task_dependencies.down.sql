@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS task_dependencies_blocker_id_idx;
+
+DROP TABLE IF EXISTS task_dependencies;
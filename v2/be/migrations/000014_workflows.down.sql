@@ -0,0 +1,9 @@
+ALTER TABLE tasks
+    DROP CONSTRAINT IF EXISTS tasks_status_fkey,
+    DROP COLUMN IF EXISTS status;
+
+DROP TABLE IF EXISTS status_transitions;
+
+DROP INDEX IF EXISTS statuses_done_idx;
+
+DROP TABLE IF EXISTS statuses;
@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS statuses (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (name <> ''),
+    position INTEGER NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT false,
+    UNIQUE(name, user_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS statuses_done_idx ON statuses (user_id) WHERE done;
+
+CREATE TABLE IF NOT EXISTS status_transitions (
+    user_id TEXT NOT NULL,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    PRIMARY KEY (user_id, from_status, to_status),
+    CONSTRAINT status_transitions_from_status_fkey FOREIGN KEY (from_status, user_id)
+        REFERENCES statuses (name, user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT status_transitions_to_status_fkey FOREIGN KEY (to_status, user_id)
+        REFERENCES statuses (name, user_id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- every existing user starts with the default board
+INSERT INTO statuses (id, user_id, name, position, done)
+SELECT gen_random_uuid()::TEXT, users.id, defaults.name, defaults.position, defaults.done
+FROM users, (VALUES ('todo', 0, false), ('in_progress', 1, false), ('review', 2, false), ('done', 3, true))
+    AS defaults (name, position, done)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO status_transitions (user_id, from_status, to_status)
+SELECT users.id, defaults.from_status, defaults.to_status
+FROM users, (VALUES ('todo', 'in_progress'), ('in_progress', 'todo'), ('in_progress', 'review'),
+    ('review', 'in_progress'), ('review', 'done'), ('done', 'review'))
+    AS defaults (from_status, to_status)
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS status TEXT;
+
+UPDATE tasks SET status = CASE WHEN completed THEN 'done' ELSE 'todo' END;
+
+ALTER TABLE tasks
+    ALTER COLUMN status SET NOT NULL,
+    ADD CONSTRAINT tasks_status_fkey FOREIGN KEY (status, user_id)
+        REFERENCES statuses (name, user_id) ON UPDATE CASCADE;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS comments_task_id_idx;
+
+DROP TABLE IF EXISTS comments;
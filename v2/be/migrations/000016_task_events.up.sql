@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS task_events (
+    id TEXT PRIMARY KEY NOT NULL,
+    task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    action TEXT NOT NULL CHECK (action IN ('created', 'updated', 'completed', 'deleted')),
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS task_events_task_id_idx ON task_events (task_id, id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS attachments_user_id_idx;
+
+DROP INDEX IF EXISTS attachments_task_id_idx;
+
+DROP TABLE IF EXISTS attachments;
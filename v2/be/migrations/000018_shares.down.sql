@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS shares_owner_id_idx;
+
+DROP INDEX IF EXISTS shares_user_id_idx;
+
+DROP TABLE IF EXISTS shares;
+
+DROP TYPE IF EXISTS share_role;
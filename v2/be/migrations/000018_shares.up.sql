@@ -0,0 +1,19 @@
+CREATE TYPE share_role AS ENUM ('viewer', 'editor');
+
+CREATE TABLE IF NOT EXISTS shares (
+    id TEXT PRIMARY KEY NOT NULL,
+    owner_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    task_id TEXT REFERENCES tasks (id) ON DELETE CASCADE,
+    project_id TEXT REFERENCES projects (id) ON DELETE CASCADE,
+    role share_role NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT shares_target_check CHECK ((task_id IS NULL) <> (project_id IS NULL)),
+    CONSTRAINT shares_owner_id_check CHECK (owner_id <> user_id),
+    CONSTRAINT shares_task_id_user_id_key UNIQUE (task_id, user_id),
+    CONSTRAINT shares_project_id_user_id_key UNIQUE (project_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS shares_user_id_idx ON shares (user_id);
+
+CREATE INDEX IF NOT EXISTS shares_owner_id_idx ON shares (owner_id);
@@ -0,0 +1,3 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assignee_id TEXT REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS tasks_assignee_id_idx ON tasks (assignee_id) WHERE assignee_id IS NOT NULL;
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS tasks_workspace_id_idx;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS workspace_id;
+
+DROP INDEX IF EXISTS workspace_members_user_id_idx;
+
+DROP INDEX IF EXISTS workspace_members_owner_key;
+
+DROP TABLE IF EXISTS workspace_members;
+
+DROP TABLE IF EXISTS workspaces;
+
+DROP TYPE IF EXISTS workspace_role;
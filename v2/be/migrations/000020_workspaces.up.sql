@@ -0,0 +1,23 @@
+CREATE TYPE workspace_role AS ENUM ('guest', 'member', 'admin', 'owner');
+
+CREATE TABLE IF NOT EXISTS workspaces (
+    id TEXT PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL CHECK (name <> ''),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id TEXT NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role workspace_role NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT workspace_members_pkey PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS workspace_members_owner_key ON workspace_members (workspace_id) WHERE role = 'owner';
+
+CREATE INDEX IF NOT EXISTS workspace_members_user_id_idx ON workspace_members (user_id);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS workspace_id TEXT REFERENCES workspaces (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS tasks_workspace_id_idx ON tasks (workspace_id) WHERE workspace_id IS NOT NULL;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS task_templates (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (name <> ''),
+    title TEXT NOT NULL CHECK (title <> ''),
+    description TEXT NOT NULL CHECK (description <> ''),
+    subtasks JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT task_templates_name_user_id_key UNIQUE (name, user_id)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tasks_user_id_archived_at_idx;
+
+ALTER TABLE tasks
+    DROP CONSTRAINT IF EXISTS tasks_archived_at_check,
+    DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ,
+    ADD CONSTRAINT tasks_archived_at_check CHECK (archived_at IS NULL OR completed);
+
+CREATE INDEX IF NOT EXISTS tasks_user_id_archived_at_idx ON tasks (user_id, archived_at) WHERE archived_at IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS tasks_version_trigger ON tasks;
+
+DROP FUNCTION IF EXISTS tasks_next_version();
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS version;
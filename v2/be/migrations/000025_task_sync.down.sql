@@ -0,0 +1,17 @@
+DROP TRIGGER IF EXISTS tasks_tombstone_trigger ON tasks;
+
+DROP FUNCTION IF EXISTS tasks_record_tombstone();
+
+DROP INDEX IF EXISTS task_tombstones_user_id_changed_xid_idx;
+
+DROP TABLE IF EXISTS task_tombstones;
+
+DROP TRIGGER IF EXISTS tasks_changes_trigger ON tasks;
+
+DROP FUNCTION IF EXISTS tasks_track_changes();
+
+DROP INDEX IF EXISTS tasks_user_id_changed_xid_idx;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS field_changed_at,
+    DROP COLUMN IF EXISTS changed_xid;
@@ -0,0 +1,61 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS changed_xid XID8 NOT NULL DEFAULT pg_current_xact_id(),
+    ADD COLUMN IF NOT EXISTS field_changed_at JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS tasks_user_id_changed_xid_idx ON tasks (user_id, changed_xid);
+
+-- every write stamps the task with its transaction, so a sync can ask for the
+-- tasks written since a snapshot, and each field with the time it changed
+CREATE OR REPLACE FUNCTION tasks_track_changes() RETURNS TRIGGER AS $$
+DECLARE
+    field TEXT;
+    old_task JSONB;
+    new_task JSONB;
+BEGIN
+    NEW.changed_xid := pg_current_xact_id();
+
+    IF TG_OP = 'UPDATE' THEN
+        old_task := to_jsonb(OLD);
+        new_task := to_jsonb(NEW);
+
+        FOREACH field IN ARRAY ARRAY['project_id', 'parent_id', 'assignee_id', 'title', 'description', 'status',
+            'completed', 'archived_at', 'deleted_at', 'due_at', 'remind_at', 'recurrence', 'priority', 'position'] LOOP
+            IF old_task -> field IS DISTINCT FROM new_task -> field THEN
+                NEW.field_changed_at := NEW.field_changed_at || jsonb_build_object(field, now());
+            END IF;
+        END LOOP;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER tasks_changes_trigger
+    BEFORE INSERT OR UPDATE ON tasks
+    FOR EACH ROW
+    EXECUTE FUNCTION tasks_track_changes();
+
+CREATE TABLE IF NOT EXISTS task_tombstones (
+    task_id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    changed_xid XID8 NOT NULL DEFAULT pg_current_xact_id()
+);
+
+CREATE INDEX IF NOT EXISTS task_tombstones_user_id_changed_xid_idx ON task_tombstones (user_id, changed_xid);
+
+-- tasks removed for good leave a tombstone behind for clients that still hold them
+CREATE OR REPLACE FUNCTION tasks_record_tombstone() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO task_tombstones (task_id, user_id)
+    VALUES (OLD.id, OLD.user_id)
+    ON CONFLICT (task_id) DO NOTHING;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER tasks_tombstone_trigger
+    AFTER DELETE ON tasks
+    FOR EACH ROW
+    EXECUTE FUNCTION tasks_record_tombstone();